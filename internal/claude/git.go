@@ -0,0 +1,116 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GitRepo describes the git working tree rooted at Root.
+//
+// In a regular checkout Root/.git is the git directory. Linked worktrees
+// (`git worktree add`) and submodules have a .git file instead, containing
+// "gitdir: <path>" that points at their private git directory.
+type GitRepo struct {
+	Root       string // working tree root (the directory containing .git)
+	GitDir     string // git directory of this working tree
+	CommonDir  string // git directory shared by all worktrees; equals GitDir outside linked worktrees
+	DotGitFile bool   // .git is a gitdir file rather than a directory
+}
+
+// OpenGitRepo inspects dir/.git. It returns an error if dir is not the root
+// of a git working tree.
+func OpenGitRepo(dir string) (*GitRepo, error) {
+	dotGit := filepath.Join(dir, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return nil, err
+	}
+	repo := &GitRepo{Root: dir, GitDir: dotGit, CommonDir: dotGit}
+	if info.IsDir() {
+		return repo, nil
+	}
+
+	gitDir, err := readGitDirFile(dotGit)
+	if err != nil {
+		return nil, err
+	}
+	repo.GitDir = gitDir
+	repo.CommonDir = gitDir
+	repo.DotGitFile = true
+
+	// Linked worktrees record the shared git directory in "commondir".
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		repo.CommonDir = filepath.Clean(common)
+	}
+	return repo, nil
+}
+
+// readGitDirFile parses a .git file ("gitdir: <path>") and returns the
+// absolute git directory it points to.
+func readGitDirFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(line), "gitdir:")
+	gitDir = strings.TrimSpace(gitDir)
+	if !ok || gitDir == "" {
+		return "", fmt.Errorf("%s: not a gitdir file", path)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	gitDir = filepath.Clean(gitDir)
+	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%s: gitdir %s does not exist", path, gitDir)
+	}
+	return gitDir, nil
+}
+
+// LinkedWorktree reports whether Root was added with `git worktree add`.
+func (r *GitRepo) LinkedWorktree() bool {
+	return r.GitDir != r.CommonDir
+}
+
+// Submodule reports whether Root is a submodule checkout, whose git
+// directory lives in the superproject's .git/modules/.
+func (r *GitRepo) Submodule() bool {
+	return r.DotGitFile && !r.LinkedWorktree()
+}
+
+// MainRoot returns the root of the main working tree, which owns CommonDir.
+// It returns "" when the main repository is bare.
+func (r *GitRepo) MainRoot() string {
+	if filepath.Base(r.CommonDir) != ".git" {
+		return ""
+	}
+	return filepath.Dir(r.CommonDir)
+}
+
+// ExcludeFile returns the repository's info/exclude file, which ignores
+// paths in every worktree without touching a tracked .gitignore.
+func (r *GitRepo) ExcludeFile() string {
+	return filepath.Join(r.CommonDir, "info", "exclude")
+}
+
+// FindGitRepo walks up from start to the nearest git working tree root.
+func FindGitRepo(start string) (*GitRepo, error) {
+	dir := start
+	for {
+		if repo, err := OpenGitRepo(dir); err == nil {
+			return repo, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("%s is not inside a git repository", start)
+		}
+		dir = parent
+	}
+}
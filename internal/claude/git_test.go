@@ -0,0 +1,99 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeWorktree lays out a main checkout at <tmp>/main and a linked worktree
+// at <tmp>/wt the way `git worktree add` does.
+func makeWorktree(t *testing.T) (main, wt string) {
+	t.Helper()
+	tmp, _ := filepath.EvalSymlinks(t.TempDir())
+	main = filepath.Join(tmp, "main")
+	wt = filepath.Join(tmp, "wt")
+	wtGitDir := filepath.Join(main, ".git", "worktrees", "wt")
+	os.MkdirAll(wtGitDir, 0755)
+	os.MkdirAll(wt, 0755)
+	os.WriteFile(filepath.Join(wtGitDir, "commondir"), []byte("../..\n"), 0644)
+	os.WriteFile(filepath.Join(wtGitDir, "gitdir"), []byte(filepath.Join(wt, ".git")+"\n"), 0644)
+	os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+wtGitDir+"\n"), 0644)
+	return main, wt
+}
+
+func TestOpenGitRepo_Regular(t *testing.T) {
+	tmp := t.TempDir()
+	os.Mkdir(filepath.Join(tmp, ".git"), 0755)
+
+	repo, err := OpenGitRepo(tmp)
+	if err != nil {
+		t.Fatalf("OpenGitRepo: %v", err)
+	}
+	if repo.LinkedWorktree() || repo.Submodule() {
+		t.Errorf("regular checkout reported as worktree=%v submodule=%v", repo.LinkedWorktree(), repo.Submodule())
+	}
+	if repo.MainRoot() != tmp {
+		t.Errorf("MainRoot = %s, want %s", repo.MainRoot(), tmp)
+	}
+	if want := filepath.Join(tmp, ".git", "info", "exclude"); repo.ExcludeFile() != want {
+		t.Errorf("ExcludeFile = %s, want %s", repo.ExcludeFile(), want)
+	}
+}
+
+func TestOpenGitRepo_LinkedWorktree(t *testing.T) {
+	main, wt := makeWorktree(t)
+
+	repo, err := OpenGitRepo(wt)
+	if err != nil {
+		t.Fatalf("OpenGitRepo: %v", err)
+	}
+	if !repo.LinkedWorktree() {
+		t.Error("expected a linked worktree")
+	}
+	if repo.Submodule() {
+		t.Error("linked worktree reported as submodule")
+	}
+	if want := filepath.Join(main, ".git"); repo.CommonDir != want {
+		t.Errorf("CommonDir = %s, want %s", repo.CommonDir, want)
+	}
+	if repo.MainRoot() != main {
+		t.Errorf("MainRoot = %s, want %s", repo.MainRoot(), main)
+	}
+}
+
+func TestOpenGitRepo_Submodule(t *testing.T) {
+	tmp, _ := filepath.EvalSymlinks(t.TempDir())
+	modDir := filepath.Join(tmp, ".git", "modules", "lib")
+	os.MkdirAll(modDir, 0755)
+	sub := filepath.Join(tmp, "lib")
+	os.Mkdir(sub, 0755)
+	// Submodules use a relative gitdir.
+	os.WriteFile(filepath.Join(sub, ".git"), []byte("gitdir: ../.git/modules/lib\n"), 0644)
+
+	repo, err := OpenGitRepo(sub)
+	if err != nil {
+		t.Fatalf("OpenGitRepo: %v", err)
+	}
+	if repo.GitDir != modDir {
+		t.Errorf("GitDir = %s, want %s", repo.GitDir, modDir)
+	}
+	if !repo.Submodule() || repo.LinkedWorktree() {
+		t.Errorf("submodule reported as worktree=%v submodule=%v", repo.LinkedWorktree(), repo.Submodule())
+	}
+}
+
+func TestOpenGitRepo_InvalidGitFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"not a gitdir file": "hello\n",
+		"missing gitdir":    "gitdir: does/not/exist\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmp := t.TempDir()
+			os.WriteFile(filepath.Join(tmp, ".git"), []byte(content), 0644)
+			if _, err := OpenGitRepo(tmp); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
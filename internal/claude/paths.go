@@ -6,22 +6,73 @@ import (
 	"path/filepath"
 )
 
-// DotClaudeDir returns the path to ~/.claude/ directory.
-func DotClaudeDir() (string, error) {
+// ConfigDirEnv is the environment variable Claude Code reads to relocate its
+// configuration directory (and .claude.json inside it).
+const ConfigDirEnv = "CLAUDE_CONFIG_DIR"
+
+// Path sources reported by the Resolve functions.
+const (
+	SourceDefault = "default"
+	SourceEnv     = "$" + ConfigDirEnv
+)
+
+// ResolvedPath is a path together with what determined it.
+type ResolvedPath struct {
+	Path   string `json:"path"`
+	Source string `json:"source"` // e.g. "default" or "$CLAUDE_CONFIG_DIR"
+}
+
+// configDirOverride returns the absolute $CLAUDE_CONFIG_DIR, if set.
+func configDirOverride() (string, bool, error) {
+	dir := os.Getenv(ConfigDirEnv)
+	if dir == "" {
+		return "", false, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", ConfigDirEnv, err)
+	}
+	return abs, true, nil
+}
+
+// ResolveDotClaudeDir returns Claude Code's configuration directory:
+// $CLAUDE_CONFIG_DIR if set, otherwise ~/.claude/.
+func ResolveDotClaudeDir() (ResolvedPath, error) {
+	if dir, ok, err := configDirOverride(); ok || err != nil {
+		return ResolvedPath{Path: dir, Source: SourceEnv}, err
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return "", err
+		return ResolvedPath{}, err
 	}
-	return filepath.Join(home, ".claude"), nil
+	return ResolvedPath{Path: filepath.Join(home, ".claude"), Source: SourceDefault}, nil
 }
 
-// ClaudeJSONPath returns the path to ~/.claude.json file.
-func ClaudeJSONPath() (string, error) {
+// ResolveClaudeJSONPath returns Claude Code's global config file: .claude.json
+// inside $CLAUDE_CONFIG_DIR if set, otherwise ~/.claude.json.
+func ResolveClaudeJSONPath() (ResolvedPath, error) {
+	if dir, ok, err := configDirOverride(); ok || err != nil {
+		return ResolvedPath{Path: filepath.Join(dir, ".claude.json"), Source: SourceEnv}, err
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return "", err
+		return ResolvedPath{}, err
 	}
-	return filepath.Join(home, ".claude.json"), nil
+	return ResolvedPath{Path: filepath.Join(home, ".claude.json"), Source: SourceDefault}, nil
+}
+
+// DotClaudeDir returns the path to Claude Code's configuration directory
+// (~/.claude/ unless overridden; see ResolveDotClaudeDir).
+func DotClaudeDir() (string, error) {
+	r, err := ResolveDotClaudeDir()
+	return r.Path, err
+}
+
+// ClaudeJSONPath returns the path to Claude Code's .claude.json file
+// (~/.claude.json unless overridden; see ResolveClaudeJSONPath).
+func ClaudeJSONPath() (string, error) {
+	r, err := ResolveClaudeJSONPath()
+	return r.Path, err
 }
 
 // FindMarkerRootFrom walks up from the given directory looking for Claude
@@ -60,23 +111,24 @@ func FindMarkerRoot() (string, error) {
 	return FindMarkerRootFrom(dir)
 }
 
-// IsGitRepo returns true if the given directory contains a .git/ directory.
-// Note: git worktrees use a .git file (not a directory); this intentionally
-// checks only for a directory to match standard repository roots.
+// IsGitRepo returns true if the given directory is the root of a git working
+// tree: it contains a .git/ directory, or a .git file pointing at the git
+// directory of a linked worktree or submodule.
 func IsGitRepo(dir string) bool {
-	info, err := os.Stat(filepath.Join(dir, ".git"))
-	return err == nil && info.IsDir()
+	_, err := OpenGitRepo(dir)
+	return err == nil
 }
 
-// ProjectRoot walks up from the current working directory looking for a .git
-// directory. Returns the directory containing .git, or an error if not found.
+// ProjectRoot walks up from the current working directory looking for a git
+// working tree root (see IsGitRepo). Returns the nearest such directory, or an
+// error if not found.
 func ProjectRoot() (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
 		return "", err
 	}
 	for {
-		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+		if IsGitRepo(dir) {
 			return dir, nil
 		}
 		parent := filepath.Dir(dir)
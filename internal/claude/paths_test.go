@@ -7,6 +7,7 @@ import (
 )
 
 func TestDotClaudeDir(t *testing.T) {
+	t.Setenv(ConfigDirEnv, "")
 	dir, err := DotClaudeDir()
 	if err != nil {
 		t.Fatal(err)
@@ -19,6 +20,7 @@ func TestDotClaudeDir(t *testing.T) {
 }
 
 func TestClaudeJSONPath(t *testing.T) {
+	t.Setenv(ConfigDirEnv, "")
 	p, err := ClaudeJSONPath()
 	if err != nil {
 		t.Fatal(err)
@@ -30,6 +32,26 @@ func TestClaudeJSONPath(t *testing.T) {
 	}
 }
 
+func TestConfigDirEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(ConfigDirEnv, dir)
+
+	r, err := ResolveDotClaudeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Path != dir || r.Source != SourceEnv {
+		t.Errorf("ResolveDotClaudeDir = %+v, want %s from %s", r, dir, SourceEnv)
+	}
+	r, err = ResolveClaudeJSONPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, ".claude.json"); r.Path != want || r.Source != SourceEnv {
+		t.Errorf("ResolveClaudeJSONPath = %+v, want %s from %s", r, want, SourceEnv)
+	}
+}
+
 func TestFindMarkerRootFrom(t *testing.T) {
 	t.Run("finds .claude dir", func(t *testing.T) {
 		tmp := t.TempDir()
@@ -147,6 +169,14 @@ func TestIsGitRepo(t *testing.T) {
 			t.Error("expected false for directory without .git/")
 		}
 	})
+
+	t.Run("true with worktree .git file", func(t *testing.T) {
+		_, wt := makeWorktree(t)
+
+		if !IsGitRepo(wt) {
+			t.Error("expected true for linked worktree")
+		}
+	})
 }
 
 func TestProjectRoot(t *testing.T) {
@@ -175,6 +205,24 @@ func TestProjectRoot(t *testing.T) {
 		}
 	})
 
+	t.Run("finds worktree root", func(t *testing.T) {
+		_, wt := makeWorktree(t)
+		subDir := filepath.Join(wt, "a", "b")
+		os.MkdirAll(subDir, 0755)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(subDir)
+
+		root, err := ProjectRoot()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if root != wt {
+			t.Errorf("got %s, want %s", root, wt)
+		}
+	})
+
 	t.Run("error when no git dir", func(t *testing.T) {
 		tmp := t.TempDir()
 
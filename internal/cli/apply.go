@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+var applyOnly []string
+
+func newApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <context> --only <source|glob>",
+		Short: "Restore selected files from a context without switching",
+		Long: "Copy a subset of a saved context's files into the live scope.\n\n" +
+			"Selectors match either a source tag (dotclaude, claudejson, claudemd, mcpjson, claudelocalmd or a custom scope's tag)\n" +
+			"or a glob against the stored path (e.g. 'dotclaude/agents/**').\n" +
+			"Other managed files and the active context marker are left unchanged.",
+		Args: cobra.ExactArgs(1),
+		RunE: runApply,
+	}
+	cmd.Flags().StringArrayVar(&applyOnly, "only", nil, "Source tag or glob to apply (repeatable)")
+	cmd.MarkFlagRequired("only")
+	return cmd
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	slug := context.Slugify(args[0])
+	if !context.ContextExists(cfg.ContextsDir(), slug) {
+		return fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+
+	result, err := context.Apply(context.ApplyOptions{
+		Name:    slug,
+		Only:    applyOnly,
+		DryRun:  dryRun,
+		Force:   force,
+		Verbose: verbose,
+		Config:  cfg,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := struct {
+		*context.ApplyResult
+		DryRun bool `json:"dryRun,omitempty"`
+	}{result, dryRun}
+	return report(out, func() {
+		if verbose || dryRun {
+			for _, f := range result.Files {
+				fmt.Printf("  %s\n", f)
+			}
+		}
+		if dryRun {
+			fmt.Printf("[dry-run] Would apply %d files from context %q\n", result.FilesApplied, result.Name)
+			return
+		}
+		fmt.Printf("Applied %d files from context %q\n", result.FilesApplied, result.Name)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/pfldy2850/claudectx/internal/client"
 	"github.com/pfldy2850/claudectx/internal/config"
 	"github.com/pfldy2850/claudectx/internal/context"
 	"github.com/pfldy2850/claudectx/internal/fileutil"
@@ -16,6 +17,8 @@ var (
 	createFromScratch bool
 	createCopyFrom    string
 	createDescription string
+	createTemplate    string
+	createVars        []string
 )
 
 func newCreateCmd() *cobra.Command {
@@ -23,7 +26,8 @@ func newCreateCmd() *cobra.Command {
 		Use:   "create <name>",
 		Short: "Create a new context",
 		Long: "Create a new context from the current state, from scratch, or by copying an existing context.\n\n" +
-			"By default, snapshots the current live files as the new context.",
+			"By default, snapshots the current live files as the new context.\n" +
+			"With --template, renders a bundled or user template (see 'claudectx templates').",
 		Args: cobra.ExactArgs(1),
 		RunE: runCreate,
 	}
@@ -31,6 +35,8 @@ func newCreateCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&createFromScratch, "from-scratch", false, "Create an empty context (no files)")
 	cmd.Flags().StringVar(&createCopyFrom, "copy-from", "", "Copy from an existing context")
 	cmd.Flags().StringVar(&createDescription, "description", "", "Description for this context")
+	cmd.Flags().StringVar(&createTemplate, "template", "", "Create from a named template")
+	cmd.Flags().StringArrayVar(&createVars, "var", nil, "Template variable as key=value (repeatable)")
 
 	return cmd
 }
@@ -44,62 +50,77 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	slug := context.Slugify(name)
 	if slug == "" {
-		return fmt.Errorf("invalid context name: %q", name)
+		return fmt.Errorf("%w: %q", context.ErrInvalidName, name)
 	}
 
 	if context.ContextExists(cfg.ContextsDir(), slug) {
-		return fmt.Errorf("context %q already exists", slug)
+		return fmt.Errorf("context %q %w", slug, context.ErrExists)
 	}
 
-	if createFromScratch && createCopyFrom != "" {
-		return fmt.Errorf("--from-scratch and --copy-from cannot be used together")
+	modes := 0
+	for _, set := range []bool{createFromScratch, createCopyFrom != "", createTemplate != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return usageErrorf("--from-scratch, --copy-from and --template cannot be used together")
 	}
 
 	switch {
+	case createTemplate != "":
+		return doCreateFromTemplate(cfg, slug, createTemplate)
 	case createFromScratch:
 		return doCreateFromScratch(cfg, slug)
 	case createCopyFrom != "":
 		return doCreateCopyFrom(cfg, slug, createCopyFrom)
 	default:
-		return doCreateFromCurrent(cfg, name, slug)
+		return doCreateFromCurrent(cmd, cfg, name)
 	}
 }
 
 // doCreateFromCurrent saves the current live state as a new context.
 // Auto-saves the previous current context first so edits aren't lost.
-func doCreateFromCurrent(cfg *config.Config, name, slug string) error {
-	context.AutoSaveCurrent(cfg, slug)
-
-	saveResult, err := context.Save(context.SaveOptions{
+func doCreateFromCurrent(cmd *cobra.Command, cfg *config.Config, name string) error {
+	saveResult, err := client.New(cfg).Save(cmd.Context(), client.SaveOptions{
 		Name:        name,
 		Description: createDescription,
 		DryRun:      dryRun,
 		Verbose:     verbose,
-		Config:      cfg,
 	})
 	if err != nil {
 		return err
 	}
 
-	if dryRun {
-		fmt.Printf("[dry-run] Would create context %q (%d files, %s)\n",
-			saveResult.Name, saveResult.Files, formatSize(saveResult.TotalSize))
-	} else {
+	if cfg.Scope != nil && cfg.Scope.InProject() {
+		ensureGitignore(cfg.Scope, dryRun)
+	}
+
+	return report(createOutput{SaveResult: saveResult, DryRun: dryRun}, func() {
+		if dryRun {
+			fmt.Printf("[dry-run] Would create context %q (%d files, %s)\n",
+				saveResult.Name, saveResult.Files, formatSize(saveResult.TotalSize))
+			return
+		}
 		fmt.Printf("Context %q created (%d files, %s)\n",
 			saveResult.Name, saveResult.Files, formatSize(saveResult.TotalSize))
-	}
+	})
+}
 
-	if cfg.Scope != nil && cfg.Scope.Type == config.ScopeProject {
-		ensureGitignore(cfg.Scope, dryRun)
-	}
-	return nil
+// createOutput is the result of the create command.
+type createOutput struct {
+	*context.SaveResult
+	From     string `json:"from,omitempty"`     // source context of --copy-from
+	Template string `json:"template,omitempty"` // template of --template
+	DryRun   bool   `json:"dryRun,omitempty"`
 }
 
 // doCreateFromScratch creates an empty context and switches to it.
 func doCreateFromScratch(cfg *config.Config, slug string) error {
 	if dryRun {
-		fmt.Printf("[dry-run] Would create empty context %q\n", slug)
-		return nil
+		return report(createOutput{SaveResult: &context.SaveResult{Name: slug}, DryRun: true}, func() {
+			fmt.Printf("[dry-run] Would create empty context %q\n", slug)
+		})
 	}
 
 	// Auto-save current context before clearing
@@ -134,24 +155,26 @@ func doCreateFromScratch(cfg *config.Config, slug string) error {
 		return err
 	}
 
-	fmt.Printf("Context %q created from scratch (clean slate)\n", slug)
-
-	if cfg.Scope != nil && cfg.Scope.Type == config.ScopeProject {
+	if cfg.Scope != nil && cfg.Scope.InProject() {
 		ensureGitignore(cfg.Scope, false)
 	}
-	return nil
+
+	return report(createOutput{SaveResult: &context.SaveResult{Name: slug, Dir: contextDir}}, func() {
+		fmt.Printf("Context %q created from scratch (clean slate)\n", slug)
+	})
 }
 
 // doCreateCopyFrom copies an existing context under a new name and switches to it.
 func doCreateCopyFrom(cfg *config.Config, slug, srcName string) error {
 	srcSlug := context.Slugify(srcName)
 	if !context.ContextExists(cfg.ContextsDir(), srcSlug) {
-		return fmt.Errorf("source context %q not found", srcSlug)
+		return fmt.Errorf("source context %q %w", srcSlug, context.ErrNotFound)
 	}
 
 	if dryRun {
-		fmt.Printf("[dry-run] Would create context %q from %q\n", slug, srcSlug)
-		return nil
+		return report(createOutput{SaveResult: &context.SaveResult{Name: slug}, From: srcSlug, DryRun: true}, func() {
+			fmt.Printf("[dry-run] Would create context %q from %q\n", slug, srcSlug)
+		})
 	}
 
 	srcDir := filepath.Join(cfg.ContextsDir(), srcSlug)
@@ -167,7 +190,9 @@ func doCreateCopyFrom(cfg *config.Config, slug, srcName string) error {
 		return fmt.Errorf("read copied manifest: %w", err)
 	}
 	manifest.Name = slug
-	manifest.Description = createDescription
+	if createDescription != "" {
+		manifest.Description = createDescription
+	}
 	manifest.UpdatedAt = time.Now()
 	if err := context.WriteManifest(dstDir, manifest); err != nil {
 		return err
@@ -183,10 +208,15 @@ func doCreateCopyFrom(cfg *config.Config, slug, srcName string) error {
 		return err
 	}
 
-	fmt.Printf("Context %q created from %q (%d files)\n", slug, srcSlug, result.FilesRestored)
-
-	if cfg.Scope != nil && cfg.Scope.Type == config.ScopeProject {
+	if cfg.Scope != nil && cfg.Scope.InProject() {
 		ensureGitignore(cfg.Scope, false)
 	}
-	return nil
+
+	out := createOutput{
+		SaveResult: &context.SaveResult{Name: slug, Dir: dstDir, Files: result.FilesRestored, TotalSize: manifest.TotalSize},
+		From:       srcSlug,
+	}
+	return report(out, func() {
+		fmt.Printf("Context %q created from %q (%d files)\n", slug, srcSlug, result.FilesRestored)
+	})
 }
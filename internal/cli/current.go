@@ -27,11 +27,14 @@ func runCurrent(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if current == "" {
-		fmt.Println("No active context.")
-		return nil
-	}
-
-	fmt.Println(current)
-	return nil
+	out := struct {
+		Name string `json:"name"`
+	}{current}
+	return report(out, func() {
+		if current == "" {
+			fmt.Println("No active context.")
+			return
+		}
+		fmt.Println(current)
+	})
 }
@@ -1,12 +1,9 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strings"
 
-	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -26,38 +23,18 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	slug := context.Slugify(args[0])
-
-	if !context.ContextExists(cfg.ContextsDir(), slug) {
-		return fmt.Errorf("context %q not found", slug)
-	}
-
-	// Prevent deleting the active context
-	current, _ := context.GetCurrent(cfg)
-	if current == slug {
-		return fmt.Errorf("cannot delete active context %q; switch to another context first", slug)
+	result, err := client.New(cfg).Delete(cmd.Context(), args[0], client.DeleteOptions{DryRun: dryRun})
+	if err != nil {
+		return err
 	}
-
-	if !force {
-		fmt.Printf("Delete context %q? [y/N] ", slug)
-		reader := bufio.NewReader(os.Stdin)
-		answer, _ := reader.ReadString('\n')
-		answer = strings.TrimSpace(strings.ToLower(answer))
-		if answer != "y" && answer != "yes" {
+	return report(result, func() {
+		switch {
+		case result.Deleted:
+			fmt.Printf("Context %q deleted.\n", result.Name)
+		case result.DryRun:
+			fmt.Printf("[dry-run] Would delete context %q\n", result.Name)
+		default:
 			fmt.Println("Cancelled.")
-			return nil
 		}
-	}
-
-	if dryRun {
-		fmt.Printf("[dry-run] Would delete context %q\n", slug)
-		return nil
-	}
-
-	if err := context.DeleteContext(cfg.ContextsDir(), slug); err != nil {
-		return err
-	}
-
-	fmt.Printf("Context %q deleted.\n", slug)
-	return nil
+	})
 }
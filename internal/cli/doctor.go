@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/claude"
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+// envOverrides are the environment variables that relocate paths.
+var envOverrides = []string{claude.ConfigDirEnv, config.HomeDirEnv, config.StorageDirEnv}
+
+// pathInfo is a resolved path together with what determined it.
+type pathInfo struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Source string `json:"source"`
+	Exists bool   `json:"exists"`
+}
+
+// scopePaths lists the live and storage paths of the scope cfg manages.
+func scopePaths(cfg *config.Config) []pathInfo {
+	scope := cfg.Scope
+	source := func(key string) string {
+		if s := scope.Sources[key]; s != "" {
+			return s
+		}
+		return claude.SourceDefault
+	}
+	var paths []pathInfo
+	add := func(name, path, source string) {
+		_, err := os.Stat(path)
+		paths = append(paths, pathInfo{name, path, source, err == nil})
+	}
+
+	add("dotClaudeDir", scope.DotClaudeDir, source("dotClaudeDir"))
+	for _, ef := range scope.ExtraFiles {
+		add(ef.Tag, ef.Path, source(ef.Tag))
+	}
+	storageSource := source("storageDir")
+	if cfg.StorageDir != scope.StorageDir {
+		storageSource = "storageDir in config"
+	}
+	add("storageDir", cfg.StorageDir, storageSource)
+	if cfg.StateDir() != cfg.StorageDir {
+		add("stateDir", cfg.StateDir(), "project root")
+	}
+	configSource := "--config"
+	if configPath == "" {
+		configSource = source("storageDir")
+		if scope.Type == config.ScopeUser {
+			home, _ := config.ResolveHomeDir()
+			configSource = home.Source
+		}
+	}
+	add("config", configFileOf(scope), configSource)
+	return paths
+}
+
+// configFileOf returns the config file loadConfig reads for scope.
+func configFileOf(scope *config.Scope) string {
+	if configPath != "" {
+		return configPath
+	}
+	return scope.ConfigFile()
+}
+
+// printPaths prints paths as an aligned list.
+func printPaths(paths []pathInfo) {
+	for _, p := range paths {
+		missing := ""
+		if !p.Exists {
+			missing = ", missing"
+		}
+		fmt.Printf("  %-14s %s (%s%s)\n", p.Name, p.Path, p.Source, missing)
+	}
+}
+
+// doctorCheck is one diagnosis made by doctor.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose path resolution and configuration",
+		Long: "Show the scope in use, every path claudectx reads or writes and what determined\n" +
+			"it (default location, project detection or an environment variable), and check\n" +
+			"that the config file parses and the active context still exists.",
+		Args: cobra.NoArgs,
+		RunE: runDoctor,
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	scope, err := config.ResolveScopeWithRoot(scopeFlag, rootFlag)
+	if err != nil {
+		return err
+	}
+
+	out := struct {
+		Scope  string            `json:"scope"`
+		Root   string            `json:"rootSource,omitempty"`
+		Env    map[string]string `json:"env"`
+		Paths  []pathInfo        `json:"paths"`
+		Checks []doctorCheck     `json:"checks"`
+	}{Scope: string(scope.Type), Root: scope.Sources["root"], Env: map[string]string{}}
+	for _, name := range envOverrides {
+		if v, ok := os.LookupEnv(name); ok {
+			out.Env[name] = v
+		}
+	}
+
+	failed := 0
+	check := func(name string, err error, ok string) {
+		c := doctorCheck{Name: name, OK: err == nil, Detail: ok}
+		if err != nil {
+			c.Detail = err.Error()
+			failed++
+		}
+		out.Checks = append(out.Checks, c)
+	}
+
+	cfg, err := config.LoadWithScope(configPath, scope)
+	detail := "parsed " + configFileOf(scope)
+	if _, statErr := os.Stat(configFileOf(scope)); os.IsNotExist(statErr) {
+		detail = "no config file, using defaults"
+	}
+	check("config", err, detail)
+	if cfg == nil {
+		cfg = &config.Config{StorageDir: scope.StorageDir, Scope: scope}
+	}
+	out.Paths = scopePaths(cfg)
+
+	current, err := context.GetCurrent(cfg)
+	switch {
+	case err != nil:
+		check("current", err, "")
+	case current == "":
+		check("current", nil, "no active context")
+	default:
+		if _, err := os.Stat(filepath.Join(cfg.ContextsDir(), current)); err != nil {
+			check("current", fmt.Errorf("active context %q is not saved in %s", current, cfg.ContextsDir()), "")
+		} else {
+			check("current", nil, "active context "+current)
+		}
+	}
+
+	if err := report(out, func() {
+		fmt.Printf("Scope: %s", out.Scope)
+		if out.Root != "" {
+			fmt.Printf(" (root from %s)", out.Root)
+		}
+		fmt.Println()
+		if len(out.Env) > 0 {
+			fmt.Println("\nEnvironment:")
+			for _, name := range envOverrides {
+				if v, ok := out.Env[name]; ok {
+					fmt.Printf("  %s=%s\n", name, v)
+				}
+			}
+		}
+		fmt.Println("\nPaths:")
+		printPaths(out.Paths)
+		fmt.Println("\nChecks:")
+		for _, c := range out.Checks {
+			mark := "ok"
+			if !c.OK {
+				mark = "FAIL"
+			}
+			fmt.Printf("  [%s] %s: %s\n", mark, c.Name, c.Detail)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		err := fmt.Errorf("doctor found %d problem(s)", failed)
+		if jsonOutput() {
+			return &reportedError{err}
+		}
+		return err
+	}
+	return nil
+}
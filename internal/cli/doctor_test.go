@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	isolateUserStorage(t)
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Project"), 0644)
+	if out, err := runCLI(t, "--root", root, "create", "work"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	type result struct {
+		Scope  string
+		Root   string `json:"rootSource"`
+		Paths  []pathInfo
+		Checks []doctorCheck
+	}
+	doctor := func() (result, error) {
+		out, err := runCLI(t, "--root", root, "-o", "json", "doctor")
+		var r result
+		if jerr := json.Unmarshal([]byte(out), &r); jerr != nil {
+			t.Fatalf("expected JSON from doctor, got %q", out)
+		}
+		return r, err
+	}
+
+	r, err := doctor()
+	if err != nil {
+		t.Fatalf("doctor failed: %v\n%+v", err, r)
+	}
+	if r.Scope != "project" || r.Root != "--root" {
+		t.Errorf("scope = %s (root from %q)", r.Scope, r.Root)
+	}
+	found := false
+	for _, p := range r.Paths {
+		if p.Name == "claudemd" {
+			found = true
+			if p.Path != filepath.Join(root, "CLAUDE.md") || !p.Exists || p.Source != "project root" {
+				t.Errorf("claudemd path = %+v", p)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no claudemd path in %+v", r.Paths)
+	}
+
+	// A current marker naming a deleted context is a problem.
+	os.WriteFile(filepath.Join(root, ".claudectx", "current"), []byte("gone\n"), 0644)
+	r, err = doctor()
+	if err == nil {
+		t.Fatal("expected doctor to fail for a missing active context")
+	}
+	for _, c := range r.Checks {
+		if c.Name == "current" && c.OK {
+			t.Errorf("current check passed: %+v", c)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editDescription string
+	editTags        []string
+	editUntags      []string
+	editLabels      []string
+	editUnlabels    []string
+)
+
+func newEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Edit a context's description, tags and labels",
+		Long: "Update the metadata stored in a context's manifest. Tags are free-form names,\n" +
+			"labels are key=value pairs (e.g. account=acme, env=prod); both can be used to\n" +
+			"filter 'claudectx list'. The saved files are not changed.",
+		Example: "  claudectx edit work --description \"Work account\" --tag client-a --label env=prod\n" +
+			"  claudectx edit work --untag client-a --unlabel env",
+		Args: cobra.ExactArgs(1),
+		RunE: runEdit,
+	}
+	cmd.Flags().StringVar(&editDescription, "description", "", "New description (empty to clear)")
+	cmd.Flags().StringArrayVar(&editTags, "tag", nil, "Add a tag (repeatable)")
+	cmd.Flags().StringArrayVar(&editUntags, "untag", nil, "Remove a tag (repeatable)")
+	cmd.Flags().StringArrayVar(&editLabels, "label", nil, "Set a label as key=value (repeatable)")
+	cmd.Flags().StringArrayVar(&editUnlabels, "unlabel", nil, "Remove the label with this key (repeatable)")
+	return cmd
+}
+
+// parseLabels turns key=value arguments into a map.
+func parseLabels(args []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, usageErrorf("invalid label %q: expected key=value", arg)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	edit := context.MetadataEdit{
+		AddTags:      editTags,
+		RemoveTags:   editUntags,
+		RemoveLabels: editUnlabels,
+	}
+	if cmd.Flags().Changed("description") {
+		edit.Description = &editDescription
+	}
+	labels, err := parseLabels(editLabels)
+	if err != nil {
+		return err
+	}
+	edit.SetLabels = labels
+	if edit.Description == nil && len(editTags)+len(editUntags)+len(editLabels)+len(editUnlabels) == 0 {
+		return usageErrorf("nothing to edit: use --description, --tag, --untag, --label or --unlabel")
+	}
+	if err := edit.Validate(); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	slug := context.Slugify(args[0])
+	if !context.ContextExists(cfg.ContextsDir(), slug) {
+		return fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+
+	if dryRun {
+		out := struct {
+			Name   string `json:"name"`
+			DryRun bool   `json:"dryRun"`
+		}{slug, true}
+		return report(out, func() {
+			fmt.Printf("[dry-run] Would update metadata of context %q\n", slug)
+		})
+	}
+
+	m, err := context.EditMetadata(cfg, slug, edit)
+	if err != nil {
+		return err
+	}
+	out := struct {
+		Name        string            `json:"name"`
+		Description string            `json:"description,omitempty"`
+		Tags        []string          `json:"tags,omitempty"`
+		Labels      map[string]string `json:"labels,omitempty"`
+	}{m.Name, m.Description, m.Tags, m.Labels}
+	return report(out, func() {
+		fmt.Printf("Context %q updated\n", slug)
+		printMetadata(m)
+	})
+}
+
+// printMetadata prints the description, tags and labels of a context.
+func printMetadata(m *context.Manifest) {
+	if m.Description != "" {
+		fmt.Printf("Description: %s\n", m.Description)
+	}
+	if len(m.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(m.Tags, ", "))
+	}
+	if len(m.Labels) > 0 {
+		fmt.Printf("Labels: %s\n", formatLabels(m.Labels))
+	}
+}
+
+// formatLabels renders labels as sorted key=value pairs.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditAndListFilters(t *testing.T) {
+	isolateUserStorage(t)
+	root := t.TempDir()
+	claudeMD := filepath.Join(root, "CLAUDE.md")
+	os.WriteFile(claudeMD, []byte("# A"), 0644)
+	if out, err := runCLI(t, "--root", root, "create", "a", "--description", "First"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	os.WriteFile(claudeMD, []byte("# B"), 0644)
+	if out, err := runCLI(t, "--root", root, "create", "b"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	if out, err := runCLI(t, "--root", root, "edit", "a", "--tag", "client-a", "--label", "env=prod"); err != nil {
+		t.Fatalf("edit failed: %v\n%s", err, out)
+	}
+	if out, err := runCLI(t, "--root", root, "edit", "b", "--tag", "client-a", "--label", "env=dev"); err != nil {
+		t.Fatalf("edit failed: %v\n%s", err, out)
+	}
+	if _, err := runCLI(t, "--root", root, "edit", "a"); ExitCode(err) != ExitUsage {
+		t.Errorf("edit without changes: ExitCode = %d, want %d", ExitCode(err), ExitUsage)
+	}
+
+	names := func(args ...string) string {
+		out, err := runCLI(t, append([]string{"--root", root, "-o", "json", "list"}, args...)...)
+		if err != nil {
+			t.Fatalf("list %v failed: %v", args, err)
+		}
+		var items []listOutput
+		json.Unmarshal([]byte(out), &items)
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	if got := names("--tag", "client-a"); got != "a,b" {
+		t.Errorf("list --tag client-a = %q, want a,b", got)
+	}
+	if got := names("--label", "env=prod"); got != "a" {
+		t.Errorf("list --label env=prod = %q, want a", got)
+	}
+	if got := names("--label", "env", "--sort", "created"); got != "b,a" {
+		t.Errorf("list --label env --sort created = %q, want b,a", got)
+	}
+	if got := names("--tag", "other"); got != "" {
+		t.Errorf("list --tag other = %q, want none", got)
+	}
+
+	// --copy-from keeps the source description unless one is given.
+	if out, err := runCLI(t, "--root", root, "create", "c", "--copy-from", "a"); err != nil {
+		t.Fatalf("create --copy-from failed: %v\n%s", err, out)
+	}
+	out, _ := runCLI(t, "--root", root, "show", "c")
+	if !strings.Contains(out, "Description: First") || !strings.Contains(out, "Labels: env=prod") {
+		t.Errorf("copied context lost its metadata:\n%s", out)
+	}
+}
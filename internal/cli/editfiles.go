@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+func newEditFilesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit-files <name> [path]",
+		Short: "Edit a saved context's files in $EDITOR without switching",
+		Long: "Open the files of a saved context in $VISUAL or $EDITOR (default vi). A working\n" +
+			"copy is edited; when the editor exits, JSON files are checked, and the snapshot\n" +
+			"and its manifest are replaced with the edited copy. The previous snapshot is\n" +
+			"kept as a revision. Give a stored path (e.g. CLAUDE.md or settings.json) to\n" +
+			"edit a single file. If the context is active, the changed live files are\n" +
+			"updated too. Locked contexts cannot be edited.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runEditFiles,
+	}
+}
+
+// editorCommand returns the user's editor command line.
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// openEditor runs the editor on paths, attached to the terminal. The editor
+// command may include arguments (e.g. "code --wait").
+func openEditor(paths []string) error {
+	editor := editorCommand()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		quoted := make([]string, len(paths))
+		for i, p := range paths {
+			quoted[i] = `"` + p + `"`
+		}
+		cmd = exec.Command("cmd", "/C", editor+" "+strings.Join(quoted, " "))
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, "sh")
+		cmd.Args = append(cmd.Args, paths...)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = promptWriter()
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+func runEditFiles(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	slug := context.Slugify(args[0])
+	relPath := ""
+	if len(args) > 1 {
+		relPath = args[1]
+	}
+
+	edit, err := context.BeginEditFiles(cfg, slug, relPath)
+	if err != nil {
+		return err
+	}
+	defer edit.Close()
+
+	if dryRun {
+		out := struct {
+			Name   string   `json:"name"`
+			Files  []string `json:"files"`
+			DryRun bool     `json:"dryRun"`
+		}{slug, edit.Paths, true}
+		return report(out, func() {
+			fmt.Printf("[dry-run] Would open %d file(s) of context %q in %s\n", len(edit.Paths), slug, editorCommand())
+		})
+	}
+
+	for {
+		if err := openEditor(edit.Paths); err != nil {
+			return err
+		}
+		err := edit.Validate(force)
+		var invalid *context.InvalidFilesError
+		if !errors.As(err, &invalid) {
+			if err != nil {
+				return err
+			}
+			break
+		}
+		for _, issue := range invalid.Issues {
+			fmt.Fprintf(os.Stderr, "  %s\n", issue)
+		}
+		if force || !confirm("The edited files have problems. Re-open the editor?") {
+			return fmt.Errorf("context %q not changed: %w", slug, err)
+		}
+	}
+
+	result, err := edit.Commit()
+	if err != nil {
+		return err
+	}
+	return report(result, func() {
+		if len(result.Modified) == 0 {
+			fmt.Printf("No changes to context %q\n", slug)
+			return
+		}
+		fmt.Printf("Context %q updated (%d file(s) changed)\n", slug, len(result.Modified))
+		for _, p := range result.Modified {
+			fmt.Printf("  %s\n", p)
+		}
+		if result.Active {
+			fmt.Printf("Context %q is active: the live files were updated too\n", slug)
+		}
+	})
+}
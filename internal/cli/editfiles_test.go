@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestEditFilesCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("editor script needs sh")
+	}
+	isolateUserStorage(t)
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Project"), 0644)
+	if out, err := runCLI(t, "--root", root, "create", "work"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	// The "editor" appends a line to every file it is given.
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\nfor f; do echo edited >> \"$f\"; done\n"), 0755)
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+
+	out, err := runCLI(t, "--root", root, "edit-files", "work", "CLAUDE.md")
+	if err != nil {
+		t.Fatalf("edit-files failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, `Context "work" updated (1 file(s) changed)`) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, ".claudectx", "contexts", "work", "CLAUDE.md"))
+	if string(data) != "# Projectedited\n" {
+		t.Errorf("snapshot CLAUDE.md = %q", data)
+	}
+	if live, _ := os.ReadFile(filepath.Join(root, "CLAUDE.md")); string(live) != "# Projectedited\n" {
+		t.Errorf("live CLAUDE.md of the active context not updated: %q", live)
+	}
+
+	// Switching away auto-saves the live files; the edits must survive.
+	if out, err := runCLI(t, "--root", root, "create", "other", "--from-scratch"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	if out, err := runCLI(t, "--root", root, "other"); err != nil {
+		t.Fatalf("switch failed: %v\n%s", err, out)
+	}
+	data, _ = os.ReadFile(filepath.Join(root, ".claudectx", "contexts", "work", "CLAUDE.md"))
+	if string(data) != "# Projectedited\n" {
+		t.Errorf("snapshot CLAUDE.md after switching away = %q", data)
+	}
+	if out, err := runCLI(t, "--root", root, "lint", "work"); err != nil {
+		t.Errorf("lint after edit failed: %v\n%s", err, out)
+	}
+
+	if _, err := runCLI(t, "--root", root, "lock", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(t, "--root", root, "edit-files", "work"); ExitCode(err) != ExitLocked {
+		t.Errorf("edit-files on a locked context: ExitCode = %d, want %d", ExitCode(err), ExitLocked)
+	}
+}
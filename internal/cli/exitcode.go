@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the claudectx binary. Scripts may depend on them, so existing
+// values must never change; new classes get new numbers.
+const (
+	ExitOK            = 0
+	ExitError         = 1  // any failure not listed below (I/O, hooks, ...)
+	ExitUsage         = 2  // invalid flags or arguments, context name or scope
+	ExitNotFound      = 3  // context, revision or template does not exist
+	ExitExists        = 4  // target context already exists
+	ExitScopeMismatch = 5  // context belongs to a different scope
+	ExitLocked        = 6  // context is locked
+	ExitActive        = 7  // operation not allowed on the active context
+	ExitConflict      = 8  // merge left unresolved conflicts
+	ExitInvalidFiles  = 9  // managed JSON files failed schema validation
+	ExitInvalidConfig = 10 // claudectx config file could not be parsed
+	ExitUncommitted   = 11 // switch would rewrite tracked files with uncommitted changes
+)
+
+// errorClasses maps wrapped sentinel errors to exit codes and the code names
+// used in JSON error output. The first match wins.
+var errorClasses = []struct {
+	target error
+	exit   int
+	code   string
+}{
+	{context.ErrNotFound, ExitNotFound, "not_found"},
+	{templates.ErrNotFound, ExitNotFound, "not_found"},
+	{context.ErrExists, ExitExists, "exists"},
+	{context.ErrScopeMismatch, ExitScopeMismatch, "scope_mismatch"},
+	{context.ErrLocked, ExitLocked, "locked"},
+	{context.ErrActive, ExitActive, "active"},
+	{context.ErrConflict, ExitConflict, "conflict"},
+	{context.ErrInvalidFiles, ExitInvalidFiles, "invalid_files"},
+	{config.ErrInvalidConfig, ExitInvalidConfig, "invalid_config"},
+	{context.ErrUncommitted, ExitUncommitted, "uncommitted"},
+	{context.ErrInvalidName, ExitUsage, "usage"},
+	{config.ErrInvalidScope, ExitUsage, "usage"},
+}
+
+// usageError marks a failure caused by how the command was invoked.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{fmt.Errorf(format, args...)}
+}
+
+// classify returns the exit code and JSON error code for err.
+func classify(err error) (int, string) {
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return ExitUsage, "usage"
+	}
+	for _, c := range errorClasses {
+		if errors.Is(err, c.target) {
+			return c.exit, c.code
+		}
+	}
+	return ExitError, "error"
+}
+
+// ExitCode returns the process exit status for an error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	code, _ := classify(err)
+	return code
+}
+
+// markUsageErrors makes flag parsing and argument validation errors of cmd and
+// its subcommands exit with ExitUsage.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &usageError{err}
+	})
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(c *cobra.Command, a []string) error {
+			if err := args(c, a); err != nil {
+				return &usageError{err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestExitCodes(t *testing.T) {
+	isolateUserStorage(t)
+	root := t.TempDir()
+	os.WriteFile(root+"/CLAUDE.md", []byte("# Project"), 0644)
+	if _, err := runCLI(t, "--root", root, "create", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{"--root", root, "show", "work"}, ExitOK},
+		{"missing context", []string{"--root", root, "show", "missing"}, ExitNotFound},
+		{"existing context", []string{"--root", root, "create", "work"}, ExitExists},
+		{"active context", []string{"--root", root, "delete", "work", "--force"}, ExitActive},
+		{"missing argument", []string{"--root", root, "show"}, ExitUsage},
+		{"unknown flag", []string{"--root", root, "list", "--bogus"}, ExitUsage},
+		{"invalid scope", []string{"--scope", "team", "list"}, ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runCLI(t, tt.args...)
+			if got := ExitCode(err); got != tt.want {
+				t.Errorf("ExitCode = %d, want %d (err: %v)", got, tt.want, err)
+			}
+		})
+	}
+
+	if _, err := runCLI(t, "--root", root, "lock", "work"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCLI(t, "--root", root, "-o", "json", "rename", "work", "other")
+	outputFormat = outputText
+	if ExitCode(err) != ExitLocked {
+		t.Errorf("rename of a locked context: ExitCode = %d, want %d", ExitCode(err), ExitLocked)
+	}
+	var doc errorOutput
+	json.Unmarshal([]byte(out), &doc)
+	if doc.Error.Code != "locked" {
+		t.Errorf("expected JSON error code \"locked\", got %+v", doc)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,14 +15,22 @@ func ensureGitignore(scope *config.Scope, isDryRun bool) {
 	projectRoot := filepath.Dir(scope.DotClaudeDir) // .claude is at project root
 
 	// Skip if not a git repository
-	if !claude.IsGitRepo(projectRoot) {
+	repo, err := claude.OpenGitRepo(projectRoot)
+	if err != nil {
 		return
 	}
 	gitignorePath := filepath.Join(projectRoot, ".gitignore")
+	name := ".gitignore"
+	if repo.LinkedWorktree() {
+		// .gitignore is tracked and shared with every other checkout; the
+		// repository's info/exclude applies to all worktrees untracked.
+		gitignorePath = repo.ExcludeFile()
+		name = "info/exclude"
+	}
 
 	data, err := os.ReadFile(gitignorePath)
 	if err != nil && !os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Warning: could not read .gitignore: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: could not read %s: %v\n", name, err)
 		return
 	}
 
@@ -36,7 +45,7 @@ func ensureGitignore(scope *config.Scope, isDryRun bool) {
 	}
 
 	if isDryRun {
-		fmt.Println("[dry-run] Would add .claudectx/ to .gitignore")
+		notice("[dry-run] Would add .claudectx/ to " + name)
 		return
 	}
 
@@ -57,11 +66,28 @@ func ensureGitignore(scope *config.Scope, isDryRun bool) {
 		mode = info.Mode()
 	}
 
+	if err := os.MkdirAll(filepath.Dir(gitignorePath), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not update %s: %v\n", name, err)
+		return
+	}
 	if err := os.WriteFile(gitignorePath, content, mode); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: could not update .gitignore: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: could not update %s: %v\n", name, err)
 		return
 	}
-	fmt.Println("Added .claudectx/ to .gitignore")
+	notice("Added .claudectx/ to " + name)
+}
+
+// confirm asks a yes/no question on stdin. Returns true without asking when
+// --force is set.
+func confirm(question string) bool {
+	if force {
+		return true
+	}
+	fmt.Fprintf(promptWriter(), "%s [y/N] ", question)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
 }
 
 func formatSize(bytes int64) string {
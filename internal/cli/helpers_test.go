@@ -184,3 +184,31 @@ func TestEnsureGitignore_SkipsNonGit(t *testing.T) {
 		t.Error("expected .gitignore to not be created in non-git directory")
 	}
 }
+
+func TestEnsureGitignore_LinkedWorktreeUsesInfoExclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	main := filepath.Join(tmpDir, "main")
+	wt := filepath.Join(tmpDir, "wt")
+	wtGitDir := filepath.Join(main, ".git", "worktrees", "wt")
+	os.MkdirAll(wtGitDir, 0755)
+	os.MkdirAll(wt, 0755)
+	os.WriteFile(filepath.Join(wtGitDir, "commondir"), []byte("../..\n"), 0644)
+	os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+wtGitDir+"\n"), 0644)
+
+	scope := &config.Scope{
+		DotClaudeDir: filepath.Join(wt, ".claude"),
+	}
+
+	ensureGitignore(scope, false)
+
+	if _, err := os.Stat(filepath.Join(wt, ".gitignore")); !os.IsNotExist(err) {
+		t.Error("expected tracked .gitignore to be left alone in a linked worktree")
+	}
+	data, err := os.ReadFile(filepath.Join(main, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatalf("expected info/exclude to be created: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != ".claudectx/" {
+		t.Errorf("expected '.claudectx/', got %q", string(data))
+	}
+}
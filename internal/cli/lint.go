@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func newLintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint <context>",
+		Short: "Validate a context's JSON config files",
+		Long: "Check settings.json, settings.local.json, .mcp.json and .claude.json in a saved\n" +
+			"context against their schemas and report problems with file and key paths.",
+		Args: cobra.ExactArgs(1),
+		RunE: runLint,
+	}
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	slug := context.Slugify(args[0])
+	issues, err := context.Lint(cfg, slug)
+	if err != nil {
+		return err
+	}
+	if issues == nil {
+		issues = []schema.Issue{}
+	}
+
+	out := struct {
+		Name   string         `json:"name"`
+		Issues []schema.Issue `json:"issues"`
+	}{slug, issues}
+	if err := report(out, func() {
+		if len(issues) == 0 {
+			fmt.Printf("Context %q: no problems found.\n", slug)
+			return
+		}
+		for _, issue := range issues {
+			fmt.Printf("  %s\n", issue)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if len(issues) > 0 {
+		err := fmt.Errorf("context %q has %d problem(s): %w", slug, len(issues), context.ErrInvalidFiles)
+		if jsonOutput() {
+			return &reportedError{err}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintAcceptsNonCommandHooks(t *testing.T) {
+	isolateUserStorage(t)
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".claude"), 0755)
+	os.WriteFile(filepath.Join(root, ".claude", "settings.json"), []byte(`{
+		"permissions": {"defaultMode": "dontAsk"},
+		"hooks": {"Stop": [{"hooks": [{"type": "prompt", "prompt": "Is the task done?"}]}]}
+	}`), 0644)
+	if out, err := runCLI(t, "--root", root, "create", "work"); err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+
+	out, err := runCLI(t, "--root", root, "lint", "work")
+	if err != nil {
+		t.Fatalf("lint failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "no problems found") {
+		t.Errorf("unexpected lint output:\n%s", out)
+	}
+}
@@ -1,16 +1,29 @@
 package cli
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/pfldy2850/claudectx/internal/client"
 	"github.com/pfldy2850/claudectx/internal/config"
 	"github.com/pfldy2850/claudectx/internal/context"
 	"github.com/spf13/cobra"
 )
 
-var listJSON bool
+var (
+	listJSON        bool
+	listAllProjects bool
+	listTags        []string
+	listLabels      []string
+	listSort        string
+)
+
+// Orders accepted by list --sort.
+var listSortOrders = []string{"name", "updated", "created", "size"}
 
 func newListCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -21,22 +34,79 @@ func newListCmd() *cobra.Command {
 		RunE:    runList,
 	}
 
-	cmd.Flags().BoolVar(&listJSON, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&listJSON, "json", false, "Output as JSON (same as --output json)")
+	cmd.Flags().BoolVar(&listAllProjects, "all-projects", false, "List the project contexts of every registered project (see 'claudectx projects')")
+	cmd.Flags().StringArrayVar(&listTags, "tag", nil, "Only list contexts with this tag (repeatable, all must match)")
+	cmd.Flags().StringArrayVar(&listLabels, "label", nil, "Only list contexts with this label, as key=value or key (repeatable)")
+	cmd.Flags().StringVar(&listSort, "sort", "name", "Sort by 'name', 'updated', 'created' or 'size' (newest and largest first)")
 
 	return cmd
 }
 
 // scopeContexts holds listing data for a single scope.
 type scopeContexts struct {
-	cfg     *config.Config
-	names   []string
-	current string
+	client   *client.Client
+	contexts []client.Info
+}
+
+// listScope lists the contexts saved in cfg's scope, filtered and sorted as
+// requested by the list flags.
+func listScope(cmd *cobra.Command, cfg *config.Config) (scopeContexts, error) {
+	c := client.New(cfg)
+	contexts, err := c.List(cmd.Context())
+	if err != nil {
+		return scopeContexts{}, err
+	}
+	return scopeContexts{client: c, contexts: selectContexts(contexts)}, nil
+}
+
+// selectContexts applies list --tag, --label and --sort to contexts.
+func selectContexts(contexts []client.Info) []client.Info {
+	selected := slices.DeleteFunc(contexts, func(c client.Info) bool {
+		for _, tag := range listTags {
+			if !c.HasTag(tag) {
+				return true
+			}
+		}
+		for _, label := range listLabels {
+			key, value, hasValue := strings.Cut(label, "=")
+			v, ok := c.Labels[key]
+			if !ok || hasValue && v != value {
+				return true
+			}
+		}
+		return false
+	})
+
+	var less func(a, b client.Info) int
+	switch listSort {
+	case "updated":
+		less = func(a, b client.Info) int { return b.UpdatedAt.Compare(a.UpdatedAt) }
+	case "created":
+		less = func(a, b client.Info) int { return b.CreatedAt.Compare(a.CreatedAt) }
+	case "size":
+		less = func(a, b client.Info) int { return cmp.Compare(b.TotalSize, a.TotalSize) }
+	default:
+		return selected // List returns name order
+	}
+	slices.SortStableFunc(selected, less)
+	return selected
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(listSortOrders, listSort) {
+		return usageErrorf("invalid --sort %q: must be one of %s", listSort, strings.Join(listSortOrders, ", "))
+	}
+	if listAllProjects {
+		if scopeFlag != "" || rootFlag != "" {
+			return usageErrorf("--all-projects cannot be used with --scope or --root")
+		}
+		return runListAllProjects(cmd)
+	}
+
 	// When no --scope/--root flags and project scope is available, show both scopes.
 	if scopeFlag == "" && rootFlag == "" {
-		if err := tryListBothScopes(); err == nil {
+		if err := tryListBothScopes(cmd); err == nil {
 			return nil
 		}
 	}
@@ -46,12 +116,12 @@ func runList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	return runListSingleScope(cfg)
+	return runListSingleScope(cmd, cfg)
 }
 
 // tryListBothScopes attempts to display both project and user scopes.
 // Returns nil on success, or an error if dual-scope display is not applicable.
-func tryListBothScopes() error {
+func tryListBothScopes(cmd *cobra.Command) error {
 	root, err := config.DetectProjectRoot()
 	if err != nil {
 		return err
@@ -66,12 +136,12 @@ func tryListBothScopes() error {
 		if err != nil {
 			return err
 		}
-		return runListSingleScope(cfg)
+		return runListSingleScope(cmd, cfg)
 	}
-	return runListBothScopes(projectScope)
+	return runListBothScopes(cmd, projectScope)
 }
 
-func runListBothScopes(projectScope *config.Scope) error {
+func runListBothScopes(cmd *cobra.Command, projectScope *config.Scope) error {
 	// Build configs for both scopes.
 	projectCfg, err := config.LoadWithScope(configPath, projectScope)
 	if err != nil {
@@ -90,22 +160,21 @@ func runListBothScopes(projectScope *config.Scope) error {
 	// Collect contexts from both scopes.
 	var scopes []scopeContexts
 	for _, cfg := range []*config.Config{projectCfg, userCfg} {
-		names, err := context.ListContexts(cfg.ContextsDir())
+		sc, err := listScope(cmd, cfg)
 		if err != nil {
 			return err
 		}
-		current, _ := context.GetCurrent(cfg)
-		scopes = append(scopes, scopeContexts{cfg: cfg, names: names, current: current})
+		scopes = append(scopes, sc)
 	}
 
-	if listJSON {
-		return listBothScopesAsJSON(scopes)
+	if listJSON || jsonOutput() {
+		return listAsJSON(scopes...)
 	}
 
 	// Check if both scopes are empty.
 	total := 0
 	for _, s := range scopes {
-		total += len(s.names)
+		total += len(s.contexts)
 	}
 	if total == 0 {
 		fmt.Println("No saved contexts.")
@@ -113,10 +182,10 @@ func runListBothScopes(projectScope *config.Scope) error {
 	}
 
 	for i, sc := range scopes {
-		if i > 0 && len(sc.names) > 0 {
+		if i > 0 && len(sc.contexts) > 0 {
 			fmt.Println()
 		}
-		if len(sc.names) == 0 {
+		if len(sc.contexts) == 0 {
 			continue
 		}
 		printScopeSection(sc)
@@ -125,92 +194,122 @@ func runListBothScopes(projectScope *config.Scope) error {
 	return nil
 }
 
-func runListSingleScope(cfg *config.Config) error {
-	names, err := context.ListContexts(cfg.ContextsDir())
+func runListSingleScope(cmd *cobra.Command, cfg *config.Config) error {
+	sc, err := listScope(cmd, cfg)
 	if err != nil {
 		return err
 	}
 
-	current, _ := context.GetCurrent(cfg)
-
-	if listJSON {
-		return listAsJSON(cfg, names, current)
+	if listJSON || jsonOutput() {
+		return listAsJSON(sc)
 	}
 
-	if len(names) == 0 {
+	if len(sc.contexts) == 0 {
 		fmt.Println("No saved contexts.")
 		return nil
 	}
 
-	sc := scopeContexts{cfg: cfg, names: names, current: current}
 	printScopeSection(sc)
 	return nil
 }
 
+// runListAllProjects lists the project scope of every registered project.
+func runListAllProjects(cmd *cobra.Command) error {
+	statuses, err := registeredProjects(cmd)
+	if err != nil {
+		return err
+	}
+	filtered := len(listTags) > 0 || len(listLabels) > 0
+	var shown []projectStatus
+	for _, p := range statuses {
+		p.list = selectContexts(p.list)
+		if !filtered || len(p.list) > 0 {
+			shown = append(shown, p)
+		}
+	}
+	statuses = shown
+
+	if listJSON || jsonOutput() {
+		items := []listOutput{}
+		for _, p := range statuses {
+			for _, item := range listItems(scopeContexts{p.client, p.list}) {
+				item.Project = p.Root
+				if item.IsCurrent {
+					item.Drift = p.Drift
+				}
+				items = append(items, item)
+			}
+		}
+		return writeJSON(os.Stdout, items)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No registered projects.")
+		return nil
+	}
+	for i, p := range statuses {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("Project: %s (%s)\n", p.Root, p.driftSummary())
+		printContexts(p.list)
+	}
+	return nil
+}
+
 func printScopeSection(sc scopeContexts) {
-	scope := sc.cfg.Scope
-	fmt.Printf("Scope: %s (%s)\n", scope.Type, scope.StorageDir)
+	fmt.Printf("Scope: %s (%s)\n", sc.client.Scope(), sc.client.StorageDir())
+	printContexts(sc.contexts)
+}
 
-	for _, name := range sc.names {
+func printContexts(contexts []client.Info) {
+	for _, c := range contexts {
 		marker := "  "
-		if name == sc.current {
+		if c.Current {
 			marker = "* "
 		}
 
-		m, err := context.ReadManifest(filepath.Join(sc.cfg.ContextsDir(), name))
-		if err != nil {
-			fmt.Printf("%s%s (error reading manifest)\n", marker, name)
-			continue
-		}
-
 		desc := ""
-		if m.Description != "" {
-			desc = fmt.Sprintf(" - %s", m.Description)
+		if c.Description != "" {
+			desc = fmt.Sprintf(" - %s", c.Description)
 		}
-		fmt.Printf("%s%s (%d files, %s)%s\n",
-			marker, name, len(m.Files), formatSize(m.TotalSize), desc)
+		badges := ""
+		if c.Locked {
+			badges = " [locked]"
+		}
+		for _, tag := range c.Tags {
+			badges += " #" + tag
+		}
+		fmt.Printf("%s%s (%d files, %s)%s%s\n",
+			marker, c.Name, len(c.Files), formatSize(c.TotalSize), badges, desc)
 	}
 }
 
 type listOutput struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Files       int    `json:"files"`
-	TotalSize   int64  `json:"totalSize"`
-	IsCurrent   bool   `json:"isCurrent"`
-	Scope       string `json:"scope,omitempty"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Files       int               `json:"files"`
+	TotalSize   int64             `json:"totalSize"`
+	IsCurrent   bool              `json:"isCurrent"`
+	Locked      bool              `json:"locked"`
+	Scope       string            `json:"scope,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	CreatedAt   string            `json:"createdAt"`
+	UpdatedAt   string            `json:"updatedAt"`
+
+	// Set by list --all-projects.
+	Project string               `json:"project,omitempty"`
+	Drift   *context.DriftResult `json:"drift,omitempty"` // of the active context
 }
 
-func buildListItems(cfg *config.Config, names []string, current string) []listOutput {
+func listAsJSON(scopes ...scopeContexts) error {
 	items := []listOutput{}
-	for _, name := range names {
-		m, err := context.ReadManifest(filepath.Join(cfg.ContextsDir(), name))
-		if err != nil {
-			continue
-		}
-		items = append(items, listOutput{
-			Name:        m.Name,
-			Description: m.Description,
-			Files:       len(m.Files),
-			TotalSize:   m.TotalSize,
-			IsCurrent:   m.Name == current,
-			Scope:       m.Scope,
-			CreatedAt:   m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:   m.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		})
-	}
-	return items
-}
-
-func listBothScopesAsJSON(scopes []scopeContexts) error {
-	allItems := []listOutput{}
 	for _, sc := range scopes {
-		allItems = append(allItems, buildListItems(sc.cfg, sc.names, sc.current)...)
+		items = append(items, listItems(sc)...)
 	}
 
-	data, err := json.MarshalIndent(allItems, "", "  ")
+	data, err := json.MarshalIndent(items, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -218,13 +317,22 @@ func listBothScopesAsJSON(scopes []scopeContexts) error {
 	return nil
 }
 
-func listAsJSON(cfg *config.Config, names []string, current string) error {
-	items := buildListItems(cfg, names, current)
-
-	data, err := json.MarshalIndent(items, "", "  ")
-	if err != nil {
-		return err
+func listItems(sc scopeContexts) []listOutput {
+	var items []listOutput
+	for _, c := range sc.contexts {
+		items = append(items, listOutput{
+			Name:        c.Name,
+			Description: c.Description,
+			Files:       len(c.Files),
+			TotalSize:   c.TotalSize,
+			IsCurrent:   c.Current,
+			Locked:      c.Locked,
+			Scope:       c.Scope,
+			Tags:        c.Tags,
+			Labels:      c.Labels,
+			CreatedAt:   c.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:   c.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
 	}
-	fmt.Println(string(data))
-	return nil
+	return items
 }
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+func newLockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lock <name>",
+		Short: "Mark a context read-only",
+		Long: "Mark a context read-only. A locked context is never overwritten by auto-save,\n" +
+			"'watch', or a save that replaces an existing context (the dashboard's\n" +
+			"overwrite action or an API save with overwrite), and cannot be renamed or\n" +
+			"deleted until unlocked.\n\n" +
+			"Switching away from a locked context with live edits offers to save the\n" +
+			"edits as a new context instead.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSetLocked(args[0], true)
+		},
+	}
+}
+
+func newUnlockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock <name>",
+		Short: "Make a locked context writable again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSetLocked(args[0], false)
+		},
+	}
+}
+
+func runSetLocked(name string, locked bool) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	slug := context.Slugify(name)
+	if !context.ContextExists(cfg.ContextsDir(), slug) {
+		return fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+
+	verb := "unlock"
+	if locked {
+		verb = "lock"
+	}
+	out := struct {
+		Name   string `json:"name"`
+		Locked bool   `json:"locked"`
+		DryRun bool   `json:"dryRun,omitempty"`
+	}{slug, locked, dryRun}
+
+	if dryRun {
+		return report(out, func() {
+			fmt.Printf("[dry-run] Would %s context %q\n", verb, slug)
+		})
+	}
+
+	if err := context.SetLocked(cfg, slug, locked); err != nil {
+		return err
+	}
+	return report(out, func() {
+		fmt.Printf("Context %q %sed.\n", slug, verb)
+	})
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/pfldy2850/claudectx/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+var mcpAllowSwitch bool
+
+func newMCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Run an MCP server over stdio exposing saved contexts as tools",
+		Long: "Speak the Model Context Protocol over stdin/stdout so Claude Code can list,\n" +
+			"inspect and compare saved contexts. Register it with:\n\n" +
+			"  claude mcp add claudectx -- claudectx mcp\n\n" +
+			"The switch_context tool is only offered with --allow-switch.",
+		Args: cobra.NoArgs,
+		RunE: runMCP,
+	}
+
+	cmd.Flags().BoolVar(&mcpAllowSwitch, "allow-switch", false, "Expose the switch_context tool")
+
+	return cmd
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	if jsonOutput() {
+		return usageErrorf("mcp does not support --output json")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	cfgs, err := pickerConfigs(cfg)
+	if err != nil {
+		return err
+	}
+
+	srv := &mcp.Server{AllowSwitch: mcpAllowSwitch, Version: Version}
+	for _, c := range cfgs {
+		// stdin carries the protocol; prompts cannot be answered.
+		c.Confirm = nil
+		srv.Scopes = append(srv.Scopes, mcp.Scope{Name: string(c.Scope.Type), Config: c})
+	}
+	return srv.Serve(cmd.Context(), os.Stdin, os.Stdout)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeInto        string
+	mergeBase        string
+	mergePrefer      string
+	mergeDescription string
+)
+
+func newMergeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge <a> <b> --into <new>",
+		Short: "Merge two contexts into a new context",
+		Long: "Merge two contexts at file level and, for JSON files, at key level.\n\n" +
+			"Changes are computed against a common ancestor: the --base context if\n" +
+			"given, otherwise the most recent snapshot shared by the revisions of a\n" +
+			"and b. Revisions are only kept by auto-saves of suspicious changes,\n" +
+			"watch, edit-files and revision restores, so often none is shared; pass\n" +
+			"--base then. Without a base, any key or file that differs between a and b\n" +
+			"is a conflict.\n" +
+			"JSON key conflicts are resolved interactively (or with --prefer);\n" +
+			"conflicting text files are written with conflict markers.",
+		Args: cobra.ExactArgs(2),
+		RunE: runMerge,
+	}
+	cmd.Flags().StringVar(&mergeInto, "into", "", "Name of the new merged context")
+	cmd.Flags().StringVar(&mergeBase, "base", "", "Common ancestor context (default: most recent shared revision)")
+	cmd.Flags().StringVar(&mergePrefer, "prefer", "", "Resolve all conflicts with side 'a' or 'b'")
+	cmd.Flags().StringVar(&mergeDescription, "description", "", "Description for the merged context")
+	cmd.MarkFlagRequired("into")
+	return cmd
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if mergePrefer != "" && mergePrefer != context.MergeSideA && mergePrefer != context.MergeSideB {
+		return usageErrorf("invalid --prefer %q: must be 'a' or 'b'", mergePrefer)
+	}
+
+	resolve := promptMergeConflict(args[0], args[1])
+	if mergePrefer != "" {
+		resolve = func(context.MergeConflict) string { return mergePrefer }
+	}
+
+	result, err := context.Merge(context.MergeOptions{
+		A:           args[0],
+		B:           args[1],
+		Base:        mergeBase,
+		Into:        mergeInto,
+		Description: mergeDescription,
+		DryRun:      dryRun,
+		Config:      cfg,
+		Resolve:     resolve,
+	})
+	var conflictErr *context.UnresolvedConflictsError
+	if errors.As(err, &conflictErr) {
+		return fmt.Errorf("%w\nresolve interactively or use --prefer a|b", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, c := range result.Marked {
+		fmt.Fprintf(os.Stderr, "Warning: %s has conflict markers; edit it before switching to %q\n", c.RelPath, result.Name)
+	}
+	out := struct {
+		*context.MergeResult
+		DryRun bool `json:"dryRun,omitempty"`
+	}{result, dryRun}
+	return report(out, func() {
+		if result.Base != "" && mergeBase == "" {
+			fmt.Printf("Using %s as merge base\n", result.Base)
+		}
+		if dryRun {
+			fmt.Printf("[dry-run] Would create merged context %q (%d files, %s)\n",
+				result.Name, result.Files, formatSize(result.TotalSize))
+			return
+		}
+		fmt.Printf("Context %q merged from %q and %q (%d files, %s)\n",
+			result.Name, context.Slugify(args[0]), context.Slugify(args[1]), result.Files, formatSize(result.TotalSize))
+	})
+}
+
+// promptMergeConflict returns a resolver that asks on stdin which side wins.
+// An empty answer leaves the conflict unresolved.
+func promptMergeConflict(a, b string) func(context.MergeConflict) string {
+	reader := bufio.NewReader(os.Stdin)
+	return func(c context.MergeConflict) string {
+		w := promptWriter()
+		where := c.RelPath
+		if c.Key != "" {
+			where = fmt.Sprintf("%s: %s", c.RelPath, c.Key)
+		}
+		fmt.Fprintf(w, "Conflict in %s\n", where)
+		fmt.Fprintf(w, "  [a] %s: %s\n", a, describeMergeValue(c.A, c.InA, c.Key == ""))
+		fmt.Fprintf(w, "  [b] %s: %s\n", b, describeMergeValue(c.B, c.InB, c.Key == ""))
+		if c.Key == "" && c.InA && c.InB {
+			fmt.Fprint(w, "Keep which side? [a/b/M(arkers)] ")
+		} else {
+			fmt.Fprint(w, "Keep which side? [a/b] ")
+		}
+		answer, _ := reader.ReadString('\n')
+		switch strings.TrimSpace(strings.ToLower(answer)) {
+		case "a":
+			return context.MergeSideA
+		case "b":
+			return context.MergeSideB
+		}
+		return ""
+	}
+}
+
+func describeMergeValue(value string, present, wholeFile bool) string {
+	switch {
+	case !present:
+		return "(deleted)"
+	case wholeFile:
+		return fmt.Sprintf("(%d bytes)", len(value))
+	}
+	return value
+}
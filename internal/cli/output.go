@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Output formats accepted by --output.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var outputFormat string
+
+// jsonOutput reports whether results should be written as JSON.
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+func validateOutputFormat() error {
+	switch outputFormat {
+	case outputText, outputJSON:
+		return nil
+	}
+	return usageErrorf("invalid --output %q: must be 'text' or 'json'", outputFormat)
+}
+
+// report writes a command's result: as a single JSON document on stdout with
+// --output json, otherwise by calling text.
+func report(result any, text func()) error {
+	if jsonOutput() {
+		return writeJSON(os.Stdout, result)
+	}
+	text()
+	return nil
+}
+
+// notice prints a side message that is not part of the command's result.
+// With --output json it goes to stderr so stdout stays valid JSON.
+func notice(format string, args ...any) {
+	w := io.Writer(os.Stdout)
+	if jsonOutput() {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, format+"\n", args...)
+}
+
+// promptWriter is where interactive questions are written.
+func promptWriter() io.Writer {
+	if jsonOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// errorOutput is the JSON document written for a failed command.
+type errorOutput struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorCode classifies an error for JSON output; see errorClasses.
+func errorCode(err error) string {
+	_, code := classify(err)
+	return code
+}
+
+// reportedError marks a failure whose details were already written as the
+// command's result; it only sets the exit status.
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
+
+// writeError writes err as a JSON error document unless it was already reported.
+func writeError(w io.Writer, err error) {
+	var reported *reportedError
+	if errors.As(err, &reported) {
+		return
+	}
+	writeJSON(w, errorOutput{Error: errorBody{Code: errorCode(err), Message: err.Error()}})
+}
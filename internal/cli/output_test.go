@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// isolateUserStorage redirects user storage, including the projects
+// registry that every command updates, to a temp dir for the rest of the test.
+func isolateUserStorage(t *testing.T) {
+	t.Helper()
+	t.Setenv(config.StorageDirEnv, t.TempDir())
+}
+
+// runCLI executes the root command with args and returns what was written
+// to stdout.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	root := newRootCmd()
+	root.SetArgs(args)
+	root.SetErr(io.Discard)
+	err = execute(root)
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out), err
+}
+
+func TestJSONOutput(t *testing.T) {
+	isolateUserStorage(t)
+	root := t.TempDir()
+	os.WriteFile(root+"/CLAUDE.md", []byte("# Project"), 0644)
+
+	out, err := runCLI(t, "--root", root, "-o", "json", "create", "work", "--description", "Work")
+	if err != nil {
+		t.Fatalf("create failed: %v\n%s", err, out)
+	}
+	var created map[string]any
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatalf("expected JSON from create, got %q", out)
+	}
+	if created["name"] != "work" || created["files"] != float64(1) {
+		t.Errorf("unexpected create output: %v", created)
+	}
+
+	out, _ = runCLI(t, "--root", root, "-o", "json", "current")
+	if strings.TrimSpace(out) != "{\n  \"name\": \"work\"\n}" {
+		t.Errorf("unexpected current output: %q", out)
+	}
+
+	out, _ = runCLI(t, "--root", root, "-o", "json", "show", "work")
+	var shown map[string]any
+	json.Unmarshal([]byte(out), &shown)
+	if shown["description"] != "Work" || shown["isCurrent"] != true {
+		t.Errorf("unexpected show output: %q", out)
+	}
+}
+
+func TestJSONErrorOutput(t *testing.T) {
+	isolateUserStorage(t)
+	root := t.TempDir()
+
+	out, err := runCLI(t, "--root", root, "-o", "json", "show", "missing")
+	if err == nil {
+		t.Fatal("expected show of a missing context to fail")
+	}
+	var doc errorOutput
+	if jsonErr := json.Unmarshal([]byte(out), &doc); jsonErr != nil {
+		t.Fatalf("expected JSON error document, got %q", out)
+	}
+	if doc.Error.Code == "" || !strings.Contains(doc.Error.Message, "missing") {
+		t.Errorf("unexpected error document: %+v", doc)
+	}
+
+	if _, err := runCLI(t, "-o", "yaml", "version"); err == nil {
+		t.Error("expected invalid --output to fail")
+	}
+	outputFormat = outputText
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Switch user and project scopes together",
+		Long: "A profile, declared under \"profiles\" in the user config, names a user context\n" +
+			"and a context for each of several project roots:\n\n" +
+			"  \"profiles\": {\n" +
+			"    \"client-a\": {\"user\": \"a-account\", \"projects\": {\"~/src/api\": \"client-a\"}}\n" +
+			"  }\n\n" +
+			"'profile use' switches all of them at once. The live files of every scope are\n" +
+			"backed up together first, and if any switch fails all scopes are rolled back.\n" +
+			"Combined backups are kept in <user storage>/profile-backups.\n\n" +
+			"A rollback only restores files and active markers: postSwitch hooks that\n" +
+			"already ran for scopes switched before the failure are not re-run or undone.",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "use <name>",
+			Short: "Switch every scope of a profile",
+			Args:  cobra.ExactArgs(1),
+			RunE:  runProfileUse,
+		},
+		&cobra.Command{
+			Use:     "list",
+			Aliases: []string{"ls"},
+			Short:   "List the declared profiles",
+			Args:    cobra.NoArgs,
+			RunE:    runProfileList,
+		},
+	)
+	return cmd
+}
+
+// loadUserConfig loads the user scope config, which declares the profiles.
+func loadUserConfig() (*config.Config, error) {
+	scope, err := config.UserScope()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadWithScope(configPath, scope)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Confirm = confirm
+	return cfg, nil
+}
+
+func runProfileUse(cmd *cobra.Command, args []string) error {
+	if scopeFlag != "" || rootFlag != "" {
+		return usageErrorf("profile use cannot be used with --scope or --root")
+	}
+	userCfg, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	profile, err := userCfg.Profile(args[0])
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	projects, err := profile.ProjectList()
+	if err != nil {
+		return fmt.Errorf("%w profile %q: %w", config.ErrInvalidConfig, args[0], err)
+	}
+
+	var steps []context.ProfileStep
+	if profile.User != "" {
+		steps = append(steps, context.ProfileStep{Config: userCfg, Name: profile.User})
+	}
+	for _, p := range projects {
+		if info, err := os.Stat(p.Root); err != nil || !info.IsDir() {
+			return fmt.Errorf("profile %q: project root %s does not exist", args[0], p.Root)
+		}
+		cfg, err := config.LoadWithScope(configPath, config.ProjectScopeAt(p.Root))
+		if err != nil {
+			return err
+		}
+		cfg.Confirm = confirm
+		steps = append(steps, context.ProfileStep{Config: cfg, Name: p.Context})
+	}
+
+	result, err := context.ApplyProfile(context.ProfileOptions{
+		Profile:    args[0],
+		Steps:      steps,
+		BackupsDir: userCfg.ProfileBackupsDir(),
+		DryRun:     dryRun,
+		Force:      force,
+		Verbose:    verbose,
+	})
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		registerProject(step.Config.Scope)
+	}
+
+	return report(result, func() {
+		for _, s := range result.Steps {
+			where := s.Scope
+			if s.Root != "" {
+				where += " " + s.Root
+			}
+			switch {
+			case s.AlreadyActive:
+				fmt.Printf("%s: already on context %q\n", where, s.Name)
+			case dryRun:
+				fmt.Printf("[dry-run] %s: would switch to context %q (%d files)\n", where, s.Name, s.FilesRestored)
+			default:
+				fmt.Printf("%s: switched to context %q (%d files)\n", where, s.Name, s.FilesRestored)
+			}
+		}
+		if result.BackupDir != "" {
+			fmt.Printf("Backup: %s\n", result.BackupDir)
+		}
+	})
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	userCfg, err := loadUserConfig()
+	if err != nil {
+		return err
+	}
+	type profileOutput struct {
+		Name string `json:"name"`
+		config.Profile
+	}
+	out := []profileOutput{}
+	for _, name := range userCfg.ProfileNames() {
+		out = append(out, profileOutput{name, userCfg.Profiles[name]})
+	}
+	return report(out, func() {
+		if len(out) == 0 {
+			fmt.Println("No profiles declared.")
+			return
+		}
+		for _, p := range out {
+			fmt.Println(p.Name)
+			if p.User != "" {
+				fmt.Printf("  user: %s\n", p.User)
+			}
+			for _, root := range slices.Sorted(maps.Keys(p.Projects)) {
+				fmt.Printf("  %s: %s\n", root, p.Projects[root])
+			}
+		}
+	})
+}
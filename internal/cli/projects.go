@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/client"
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/projects"
+	"github.com/spf13/cobra"
+)
+
+func newProjectsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "projects",
+		Short: "Manage the registry of projects using claudectx",
+		Long: "claudectx records every project root where project or local scope contexts are\n" +
+			"used in projects.json in the user storage dir. Roots that no longer exist are\n" +
+			"pruned whenever the registry is listed.",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List registered projects with their active context and drift",
+		Args:    cobra.NoArgs,
+		RunE:    runProjectsList,
+	})
+	return cmd
+}
+
+// projectStatus summarizes the project scope of a registered root.
+type projectStatus struct {
+	Root     string               `json:"root"`
+	Contexts int                  `json:"contexts"`
+	Current  string               `json:"current,omitempty"`
+	Drift    *context.DriftResult `json:"drift,omitempty"`
+	Error    string               `json:"error,omitempty"`
+	client   *client.Client
+	list     []client.Info
+}
+
+// driftSummary describes the drift of the active context in a few words.
+func (p projectStatus) driftSummary() string {
+	switch {
+	case p.Error != "":
+		return "error: " + p.Error
+	case p.Current == "":
+		return "no active context"
+	case p.Drift == nil:
+		return p.Current + ", not saved"
+	case p.Drift.Clean():
+		return p.Current + ", clean"
+	default:
+		n := len(p.Drift.Added) + len(p.Drift.Removed) + len(p.Drift.Modified)
+		return fmt.Sprintf("%s, %d changed", p.Current, n)
+	}
+}
+
+// registeredProjects prunes the registry and returns the status of every
+// remaining project.
+func registeredProjects(cmd *cobra.Command) ([]projectStatus, error) {
+	path, err := projects.RegistryFile()
+	if err != nil {
+		return nil, err
+	}
+	kept, removed, err := projects.Prune(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range removed {
+		notice("Pruned %s (no longer exists)", p.Root)
+	}
+
+	statuses := []projectStatus{}
+	for _, p := range kept {
+		statuses = append(statuses, projectStatusAt(cmd, p.Root))
+	}
+	return statuses, nil
+}
+
+// projectStatusAt reads the contexts, active context and its drift of the
+// project scope at root. Failures are recorded in Error.
+func projectStatusAt(cmd *cobra.Command, root string) projectStatus {
+	status := projectStatus{Root: root}
+	cfg, err := config.LoadWithScope(configPath, config.ProjectScopeAt(root))
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.client = client.New(cfg)
+	if status.list, err = status.client.List(cmd.Context()); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Contexts = len(status.list)
+	if status.Current, err = status.client.Current(cmd.Context()); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if status.Current != "" {
+		// A current marker without a saved context has no drift to report.
+		status.Drift, _ = status.client.Diff(cmd.Context(), status.Current)
+	}
+	return status
+}
+
+func runProjectsList(cmd *cobra.Command, args []string) error {
+	statuses, err := registeredProjects(cmd)
+	if err != nil {
+		return err
+	}
+	return report(statuses, func() {
+		if len(statuses) == 0 {
+			fmt.Println("No registered projects.")
+			return
+		}
+		for _, p := range statuses {
+			fmt.Printf("%s (%d contexts; %s)\n", p.Root, p.Contexts, p.driftSummary())
+		}
+	})
+}
+
+// registerProject records the project root of scope in the registry once
+// claudectx storage exists there. Failures only produce a verbose notice:
+// the registry is a convenience and must never fail a command.
+func registerProject(scope *config.Scope) {
+	if scope == nil || scope.Root == "" || dryRun {
+		return
+	}
+	if _, err := os.Stat(scope.StorageDir); err != nil {
+		return
+	}
+	path, err := projects.RegistryFile()
+	if err == nil {
+		err = projects.Register(path, scope.Root, time.Now())
+	}
+	if err != nil && verbose {
+		notice("Could not register project %s: %v", scope.Root, err)
+	}
+}
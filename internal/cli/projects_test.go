@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectsRegistry(t *testing.T) {
+	isolateUserStorage(t)
+	a := t.TempDir()
+	b := t.TempDir()
+	os.WriteFile(filepath.Join(a, "CLAUDE.md"), []byte("# A"), 0644)
+	os.WriteFile(filepath.Join(b, "CLAUDE.md"), []byte("# B"), 0644)
+
+	for _, root := range []string{a, b} {
+		if out, err := runCLI(t, "--root", root, "create", "work"); err != nil {
+			t.Fatalf("create failed: %v\n%s", err, out)
+		}
+	}
+	// Dry runs leave no trace in the registry.
+	c := t.TempDir()
+	os.Mkdir(filepath.Join(c, ".claudectx"), 0755)
+	runCLI(t, "--root", c, "--dry-run", "list")
+
+	os.WriteFile(filepath.Join(b, "CLAUDE.md"), []byte("# B edited"), 0644)
+
+	out, err := runCLI(t, "-o", "json", "projects", "list")
+	if err != nil {
+		t.Fatalf("projects list failed: %v\n%s", err, out)
+	}
+	var statuses []projectStatus
+	if err := json.Unmarshal([]byte(out), &statuses); err != nil {
+		t.Fatalf("expected JSON, got %q", out)
+	}
+	got := map[string]projectStatus{}
+	for _, s := range statuses {
+		got[s.Root] = s
+	}
+	if len(got) != 2 {
+		t.Fatalf("registered projects = %+v, want %s and %s", statuses, a, b)
+	}
+	if s := got[a]; s.Current != "work" || s.Contexts != 1 || s.Drift == nil || !s.Drift.Clean() {
+		t.Errorf("project a = %+v, want clean work", s)
+	}
+	if s := got[b]; s.Drift == nil || len(s.Drift.Modified) != 1 {
+		t.Errorf("project b = %+v, want CLAUDE.md modified", s)
+	}
+
+	// Removed roots are pruned.
+	os.RemoveAll(a)
+	out, _ = runCLI(t, "list", "--all-projects")
+	if strings.Contains(out, "Project: "+a) || !strings.Contains(out, "Project: "+b+" (work, 1 changed)") {
+		t.Errorf("unexpected list --all-projects output:\n%s", out)
+	}
+	out, _ = runCLI(t, "-o", "json", "projects", "list")
+	if strings.Contains(out, a) {
+		t.Errorf("%s was not pruned: %s", a, out)
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+func newRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rename <old> <new>",
+		Aliases: []string{"mv"},
+		Short:   "Rename a saved context",
+		Args:    cobra.ExactArgs(2),
+		RunE:    runRename,
+	}
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	oldSlug := context.Slugify(args[0])
+	newSlug := context.Slugify(args[1])
+
+	out := struct {
+		From   string `json:"from"`
+		To     string `json:"to"`
+		DryRun bool   `json:"dryRun,omitempty"`
+	}{oldSlug, newSlug, dryRun}
+
+	if dryRun {
+		return report(out, func() {
+			fmt.Printf("[dry-run] Would rename context %q to %q\n", oldSlug, newSlug)
+		})
+	}
+
+	if err := context.RenameContext(cfg, oldSlug, newSlug); err != nil {
+		return err
+	}
+	return report(out, func() {
+		fmt.Printf("Context %q renamed to %q.\n", oldSlug, newSlug)
+	})
+}
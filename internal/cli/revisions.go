@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/spf13/cobra"
+)
+
+var revisionsRestore string
+
+func newRevisionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revisions <name>",
+		Short: "List or restore previous snapshots of a context",
+		Long: "List the revisions kept when a context snapshot was overwritten (for example\n" +
+			"when auto-save detected suspicious changes), or restore one with --restore.\n\n" +
+			"Restoring replaces the saved snapshot only; switch to the context to apply it.",
+		Args: cobra.ExactArgs(1),
+		RunE: runRevisions,
+	}
+	cmd.Flags().StringVar(&revisionsRestore, "restore", "", "Revision ID to restore into the context")
+	return cmd
+}
+
+func runRevisions(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	slug := context.Slugify(args[0])
+	if !context.ContextExists(cfg.ContextsDir(), slug) {
+		return fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+
+	if revisionsRestore != "" {
+		out := struct {
+			Name     string `json:"name"`
+			Revision string `json:"revision"`
+			DryRun   bool   `json:"dryRun,omitempty"`
+		}{slug, revisionsRestore, dryRun}
+		if dryRun {
+			return report(out, func() {
+				fmt.Printf("[dry-run] Would restore revision %s of context %q\n", revisionsRestore, slug)
+			})
+		}
+		if err := context.RestoreRevision(cfg, slug, revisionsRestore); err != nil {
+			return err
+		}
+		return report(out, func() {
+			fmt.Printf("Context %q restored from revision %s\n", slug, revisionsRestore)
+		})
+	}
+
+	revs, err := context.ListRevisions(cfg, slug)
+	if err != nil {
+		return err
+	}
+	out := []revisionOutput{}
+	for _, r := range revs {
+		out = append(out, revisionOutput{
+			ID:        r.ID,
+			Files:     len(r.Manifest.Files),
+			TotalSize: r.Manifest.TotalSize,
+			UpdatedAt: r.Manifest.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	return report(out, func() {
+		if len(revs) == 0 {
+			fmt.Printf("No revisions of context %q.\n", slug)
+			return
+		}
+		for _, r := range out {
+			fmt.Printf("  %s (%d files, %s)\n", r.ID, r.Files, formatSize(r.TotalSize))
+		}
+	})
+}
+
+// revisionOutput describes one kept revision.
+type revisionOutput struct {
+	ID        string `json:"id"`
+	Files     int    `json:"files"`
+	TotalSize int64  `json:"totalSize"`
+	UpdatedAt string `json:"updatedAt"`
+}
@@ -1,10 +1,12 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/pfldy2850/claudectx/internal/client"
 	"github.com/pfldy2850/claudectx/internal/config"
 	"github.com/pfldy2850/claudectx/internal/context"
 	"github.com/pfldy2850/claudectx/internal/ui"
@@ -19,6 +21,13 @@ var (
 	configPath string
 	scopeFlag  string
 	rootFlag   string
+
+	switchUntrackedOnly bool
+	switchSkipWorktree  bool
+
+	// loadedScope is the scope of the last loadConfig call, registered in the
+	// projects registry after the command succeeds.
+	loadedScope *config.Scope
 )
 
 func newRootCmd() *cobra.Command {
@@ -26,9 +35,12 @@ func newRootCmd() *cobra.Command {
 		Use:   "claudectx [context]",
 		Short: "Claude Code context management tool",
 		Long: "Manage Claude Code configuration contexts by creating and switching snapshots.\n\n" +
-			"Supports two scopes:\n" +
+			"Supported scopes:\n" +
 			"  user    — manages ~/.claude/ and ~/.claude.json (default outside git repos)\n" +
-			"  project — manages <project-root>/.claude/ and <project-root>/CLAUDE.md (default inside projects)\n\n" +
+			"  project — manages <project-root>/.claude/ and <project-root>/CLAUDE.md (default inside projects)\n" +
+			"  local   — manages <project-root>/.claude/settings.local.json and CLAUDE.local.md (--scope local)\n" +
+			"  managed — manages machine-wide managed-settings.json policy (--scope managed, needs root)\n" +
+			"  custom  — any scope declared under \"scopes\" in ~/.claudectx/config.json (--scope <name>)\n\n" +
 			"Project root detection (highest priority first):\n" +
 			"  1. --root flag (explicit path)\n" +
 			"  2. Claude marker files (.claude/, CLAUDE.md, .claudectx/)\n" +
@@ -36,6 +48,21 @@ func newRootCmd() *cobra.Command {
 			"  4. Current directory fallback (with --scope project)\n\n" +
 			"Use --scope to override auto-detection, --root to set an explicit project root.",
 		Args: cobra.MaximumNArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(); err != nil {
+				return err
+			}
+			if jsonOutput() {
+				// Errors are written as JSON by Execute.
+				cmd.Root().SilenceErrors = true
+				cmd.Root().SilenceUsage = true
+			}
+			loadedScope = nil
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			registerProject(loadedScope)
+		},
 		RunE: runRoot,
 	}
 
@@ -43,24 +70,56 @@ func newRootCmd() *cobra.Command {
 	root.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show what would be done without making changes")
 	root.PersistentFlags().BoolVarP(&force, "force", "f", false, "Force operation (skip confirmations)")
 	root.PersistentFlags().StringVar(&configPath, "config", "", "Path to config file")
-	root.PersistentFlags().StringVar(&scopeFlag, "scope", "", "Scope: 'user' or 'project' (auto-detects if omitted)")
+	root.PersistentFlags().StringVar(&scopeFlag, "scope", "", "Scope: 'user', 'project', 'local', 'managed' or a custom scope from config.json (auto-detects user or project if omitted)")
 	root.PersistentFlags().StringVar(&rootFlag, "root", "", "Explicit project root directory (implies project scope)")
+	root.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Output format: 'text' or 'json'")
+	root.Flags().BoolVar(&switchUntrackedOnly, "untracked-only", false, "Leave files tracked by git untouched when switching")
+	root.Flags().BoolVar(&switchSkipWorktree, "skip-worktree", false, "Mark rewritten tracked files with git update-index --skip-worktree")
+	root.MarkFlagsMutuallyExclusive("untracked-only", "skip-worktree")
 
 	root.AddCommand(
 		newCreateCmd(),
+		newApplyCmd(),
+		newMergeCmd(),
+		newTemplatesCmd(),
+		newRevisionsCmd(),
+		newLintCmd(),
 		newListCmd(),
 		newShowCmd(),
+		newEditCmd(),
+		newEditFilesCmd(),
+		newRenameCmd(),
 		newDeleteCmd(),
+		newLockCmd(),
+		newUnlockCmd(),
 		newCurrentCmd(),
+		newUICmd(),
+		newServeCmd(),
+		newMCPCmd(),
+		newWatchCmd(),
+		newProjectsCmd(),
+		newProfileCmd(),
+		newDoctorCmd(),
 		newVersionCmd(),
 	)
+	markUsageErrors(root)
 
 	return root
 }
 
-// Execute runs the root command.
+// Execute runs the root command. With --output json, a failure is written to
+// stdout as {"error": {"code": ..., "message": ...}}. Use ExitCode to turn the
+// returned error into the process exit status.
 func Execute() error {
-	return newRootCmd().Execute()
+	return execute(newRootCmd())
+}
+
+func execute(root *cobra.Command) error {
+	err := root.Execute()
+	if err != nil && jsonOutput() {
+		writeError(os.Stdout, err)
+	}
+	return err
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
@@ -71,64 +130,169 @@ func runRoot(cmd *cobra.Command, args []string) error {
 
 	// If a context name is provided, switch to it
 	if len(args) == 1 {
-		return switchContext(cfg, args[0])
+		return switchContext(cmd, cfg, args[0])
 	}
 
 	// No arguments — interactive selection
-	return interactiveSelect(cfg)
+	return interactiveSelect(cmd, cfg)
 }
 
-func switchContext(cfg *config.Config, name string) error {
-	slug := context.Slugify(name)
-	if !context.ContextExists(cfg.ContextsDir(), slug) {
-		return fmt.Errorf("context %q not found; use 'claudectx create %s' to create it", slug, slug)
+func switchContext(cmd *cobra.Command, cfg *config.Config, name string) error {
+	var tracked string
+	switch {
+	case switchUntrackedOnly:
+		tracked = config.GitTrackedSkip
+	case switchSkipWorktree:
+		tracked = config.GitTrackedSkipWorktree
 	}
-
-	current, _ := context.GetCurrent(cfg)
-	if current == slug && !force {
-		fmt.Printf("Already on context %q\n", slug)
-		return nil
-	}
-
-	result, err := context.Restore(context.RestoreOptions{
-		Name:    name,
+	result, err := client.New(cfg).Switch(cmd.Context(), name, client.SwitchOptions{
 		DryRun:  dryRun,
 		Force:   force,
 		Verbose: verbose,
-		Config:  cfg,
+		Tracked: tracked,
 	})
+	if errors.Is(err, context.ErrNotFound) {
+		return fmt.Errorf("%w; use 'claudectx create %s' to create it", err, context.Slugify(name))
+	}
 	if err != nil {
 		return err
 	}
 
-	if dryRun {
-		fmt.Printf("[dry-run] Would switch to context %q (%d files)\n", result.Name, result.FilesRestored)
-		return nil
-	}
-
-	fmt.Printf("Switched to context %q (%d files)\n", result.Name, result.FilesRestored)
-	return nil
+	return report(result, func() {
+		switch {
+		case result.AlreadyActive:
+			fmt.Printf("Already on context %q\n", result.Name)
+		case dryRun:
+			fmt.Printf("[dry-run] Would switch to context %q (%d files)\n", result.Name, result.FilesRestored)
+		default:
+			fmt.Printf("Switched to context %q (%d files)\n", result.Name, result.FilesRestored)
+		}
+		if n := len(result.SkippedTracked); n > 0 {
+			fmt.Printf("Left %d git-tracked file(s) untouched\n", n)
+		}
+		if n := len(result.SkipWorktree); n > 0 {
+			fmt.Printf("Marked %d tracked file(s) with --skip-worktree\n", n)
+		}
+	})
 }
 
-func interactiveSelect(cfg *config.Config) error {
-	names, err := context.ListContexts(cfg.ContextsDir())
+func interactiveSelect(cmd *cobra.Command, cfg *config.Config) error {
+	if jsonOutput() {
+		return usageErrorf("a context name is required with --output json")
+	}
+	cfgs, err := pickerConfigs(cfg)
 	if err != nil {
 		return err
 	}
-	if len(names) == 0 {
+
+	byScope := map[string]*config.Config{}
+	var tabs []ui.PickerTab
+	active, total := 0, 0
+	for _, c := range cfgs {
+		items, err := pickerItems(c)
+		if err != nil {
+			return err
+		}
+		scope := string(c.Scope.Type)
+		if c.Scope.Type == cfg.Scope.Type {
+			active = len(tabs)
+		}
+		byScope[scope] = c
+		tabs = append(tabs, ui.PickerTab{Scope: scope, Items: items})
+		total += len(items)
+	}
+	if total == 0 {
 		fmt.Println("No saved contexts. Use 'claudectx create <name>' to create one.")
 		return nil
 	}
 
+	var actions ui.PickerActions
+	if !dryRun {
+		actions = ui.PickerActions{
+			Delete: func(scope, name string) ([]ui.PickerItem, error) {
+				c := byScope[scope]
+				if err := context.DeleteContext(c, name); err != nil {
+					return nil, err
+				}
+				return pickerItems(c)
+			},
+			Rename: func(scope, oldName, newName string) ([]ui.PickerItem, error) {
+				c := byScope[scope]
+				if err := context.RenameContext(c, oldName, newName); err != nil {
+					return nil, err
+				}
+				return pickerItems(c)
+			},
+			Duplicate: func(scope, name, newName string) ([]ui.PickerItem, error) {
+				c := byScope[scope]
+				if err := context.CopyContext(c, name, newName); err != nil {
+					return nil, err
+				}
+				return pickerItems(c)
+			},
+		}
+	}
+
+	selected, err := ui.RunPicker(tabs, active, actions)
+	if err != nil {
+		return err
+	}
+	if selected == nil {
+		return nil // user cancelled
+	}
+
+	return switchContext(cmd, byScope[selected.Scope], selected.Name)
+}
+
+// pickerConfigs returns the configs shown as picker tabs: project and user
+// scope when no scope was forced and a project is detected, otherwise just cfg.
+func pickerConfigs(cfg *config.Config) ([]*config.Config, error) {
+	if scopeFlag != "" || rootFlag != "" {
+		return []*config.Config{cfg}, nil
+	}
+	root, err := config.DetectProjectRoot()
+	if err != nil {
+		return []*config.Config{cfg}, nil
+	}
+	userScope, err := config.UserScope()
+	if err != nil {
+		return []*config.Config{cfg}, nil
+	}
+	projectScope := config.ProjectScopeAt(root)
+	if projectScope.StorageDir == userScope.StorageDir {
+		return []*config.Config{cfg}, nil
+	}
+
+	var cfgs []*config.Config
+	for _, scope := range []*config.Scope{projectScope, userScope} {
+		c, err := config.LoadWithScope(configPath, scope)
+		if err != nil {
+			return nil, err
+		}
+		c.Confirm = confirm
+		cfgs = append(cfgs, c)
+	}
+	return cfgs, nil
+}
+
+// pickerItems loads the manifests of all contexts in a scope for the picker.
+func pickerItems(cfg *config.Config) ([]ui.PickerItem, error) {
+	names, err := context.ListContexts(cfg.ContextsDir())
+	if err != nil {
+		return nil, err
+	}
 	current, _ := context.GetCurrent(cfg)
 
-	// Load manifests for display
-	var items []ui.PickerItem
+	items := []ui.PickerItem{}
 	for _, name := range names {
 		m, err := context.ReadManifest(filepath.Join(cfg.ContextsDir(), name))
 		if err != nil {
 			continue
 		}
+		paths := make([]string, len(m.Files))
+		for i, f := range m.Files {
+			paths[i] = f.RelPath
+		}
 		items = append(items, ui.PickerItem{
 			Name:        m.Name,
 			Description: m.Description,
@@ -136,18 +300,12 @@ func interactiveSelect(cfg *config.Config) error {
 			TotalSize:   m.TotalSize,
 			UpdatedAt:   m.UpdatedAt,
 			IsCurrent:   m.Name == current,
+			Locked:      m.Locked,
+			OAuthEmail:  m.OAuthEmail,
+			Paths:       paths,
 		})
 	}
-
-	selected, err := ui.RunPicker(items)
-	if err != nil {
-		return err
-	}
-	if selected == "" {
-		return nil // user cancelled
-	}
-
-	return switchContext(cfg, selected)
+	return items, nil
 }
 
 func loadConfig() (*config.Config, error) {
@@ -161,5 +319,7 @@ func loadConfig() (*config.Config, error) {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		return nil, err
 	}
+	cfg.Confirm = confirm
+	loadedScope = scope
 	return cfg, nil
 }
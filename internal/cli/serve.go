@@ -0,0 +1,119 @@
+package cli
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/client"
+	"github.com/pfldy2850/claudectx/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveSocket   string
+	serveInterval time.Duration
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a local HTTP/JSON API over a Unix socket",
+		Long: "Serve a small HTTP/JSON API on a Unix socket so editors and status bars can\n" +
+			"list contexts, read the active context and drift, switch and save without\n" +
+			"spawning a process. GET /v1/events streams server-sent events when the\n" +
+			"active context changes. See the README for the endpoints.",
+		Args: cobra.NoArgs,
+		RunE: runServe,
+	}
+
+	cmd.Flags().StringVar(&serveSocket, "socket", "", "Socket path (default <storage>/claudectx.sock)")
+	cmd.Flags().DurationVar(&serveInterval, "poll", 2*time.Second, "How often to check for context switches made outside the server")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if jsonOutput() {
+		return usageErrorf("serve does not support --output json")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	cfgs, err := pickerConfigs(cfg)
+	if err != nil {
+		return err
+	}
+
+	var scopes []server.Scope
+	for _, c := range cfgs {
+		// Nobody is at the terminal to answer prompts.
+		c.Confirm = nil
+		scopes = append(scopes, server.Scope{Name: string(c.Scope.Type), Client: client.New(c)})
+	}
+
+	socket := serveSocket
+	if socket == "" {
+		socket = filepath.Join(cfg.StorageDir, "claudectx.sock")
+	}
+	ln, err := listenUnix(socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := server.New(scopes)
+	go srv.Watch(ctx, serveInterval)
+
+	httpServer := &http.Server{Handler: srv}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := gocontext.WithTimeout(gocontext.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Listening on %s\n", socket)
+	if err := httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// listenUnix listens on a Unix socket readable only by the current user. A
+// stale socket left by a crashed server is replaced; a live one is an error.
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create socket dir: %w", err)
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another server is already listening on %s", path)
+		}
+		os.Remove(path)
+	}
+	ln, err := listenPrivate(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
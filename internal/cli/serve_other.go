@@ -0,0 +1,10 @@
+//go:build !unix
+
+package cli
+
+import "net"
+
+// listenPrivate creates the socket; there is no umask on this platform.
+func listenPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
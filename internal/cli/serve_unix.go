@@ -0,0 +1,17 @@
+//go:build unix
+
+package cli
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// listenPrivate creates the socket with a 0077 umask so it is never
+// connectable by other users, not even before it is chmod-ed.
+func listenPrivate(path string) (net.Listener, error) {
+	old := unix.Umask(0077)
+	defer unix.Umask(old)
+	return net.Listen("unix", path)
+}
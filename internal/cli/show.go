@@ -2,9 +2,9 @@ package cli
 
 import (
 	"fmt"
-	"path/filepath"
+	"os"
 
-	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -23,25 +23,31 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	slug := context.Slugify(args[0])
-	m, err := context.ReadManifest(filepath.Join(cfg.ContextsDir(), slug))
+	m, err := client.New(cfg).Show(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("context %q not found", slug)
+		return err
+	}
+	paths := scopePaths(cfg)
+	if jsonOutput() {
+		return writeJSON(os.Stdout, struct {
+			*client.Info
+			Paths []pathInfo `json:"paths"`
+		}{m, paths})
 	}
 
-	current, _ := context.GetCurrent(cfg)
 	activeMarker := ""
-	if slug == current {
+	if m.Current {
 		activeMarker = " (active)"
 	}
 
 	fmt.Printf("Context: %s%s\n", m.Name, activeMarker)
-	if m.Description != "" {
-		fmt.Printf("Description: %s\n", m.Description)
-	}
+	printMetadata(m.Manifest)
 	if m.Scope != "" {
 		fmt.Printf("Scope: %s\n", m.Scope)
 	}
+	if m.Locked {
+		fmt.Println("Locked: yes")
+	}
 	if m.OAuthEmail != "" {
 		fmt.Printf("OAuth Email: %s\n", m.OAuthEmail)
 	}
@@ -56,5 +62,8 @@ func runShow(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s (%s) [%s]\n", f.RelPath, formatSize(f.Size), f.Source)
 	}
 
+	fmt.Println("\nPaths:")
+	printPaths(paths)
+
 	return nil
 }
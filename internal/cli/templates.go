@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+func newTemplatesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "templates",
+		Short: "List context templates available to 'create --template'",
+		Long: "List bundled templates and user templates.\n\n" +
+			"User templates are directories under <storage-dir>/templates/ laid out like a\n" +
+			"context (CLAUDE.md, .mcp.json, dotclaude/...) with an optional template.json\n" +
+			"({\"description\": ..., \"scope\": ...}). Files are rendered with Go templates:\n" +
+			"{{.ProjectName}}, {{.ContextName}}, {{env \"NAME\"}}, {{var \"key\"}}.",
+		Args: cobra.NoArgs,
+		RunE: runTemplates,
+	}
+}
+
+func runTemplates(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	all, err := templates.List(templateDirs(cfg)...)
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		if all == nil {
+			all = []templates.Template{}
+		}
+		return writeJSON(os.Stdout, all)
+	}
+	if len(all) == 0 {
+		fmt.Println("No templates available.")
+		return nil
+	}
+	for _, t := range all {
+		scope := t.Scope
+		if scope == "" {
+			scope = "any"
+		}
+		desc := ""
+		if t.Description != "" {
+			desc = fmt.Sprintf(" - %s", t.Description)
+		}
+		fmt.Printf("  %s [%s, %s]%s\n", t.Name, scope, t.Source, desc)
+	}
+	return nil
+}
+
+// templateDirs returns the user template directories for the active scope,
+// followed by the user-scope storage directory when it differs.
+func templateDirs(cfg *config.Config) []string {
+	dirs := []string{cfg.TemplatesDir()}
+	if userStorage, err := config.DefaultStorageDir(); err == nil {
+		userDir := filepath.Join(userStorage, "templates")
+		if userDir != dirs[0] {
+			dirs = append(dirs, userDir)
+		}
+	}
+	return dirs
+}
+
+// doCreateFromTemplate renders a template into a new context and switches to it.
+func doCreateFromTemplate(cfg *config.Config, slug, name string) error {
+	tpl, err := templates.Find(name, templateDirs(cfg)...)
+	if err != nil {
+		return err
+	}
+	if tpl.Scope != "" && tpl.Scope != string(cfg.Scope.Type) {
+		return fmt.Errorf("%w: template %q is for %s scope, but current scope is %s", context.ErrScopeMismatch, name, tpl.Scope, cfg.Scope.Type)
+	}
+
+	vars := map[string]string{}
+	for _, kv := range createVars {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return usageErrorf("invalid --var %q: expected key=value", kv)
+		}
+		vars[key] = value
+	}
+
+	projectRoot := filepath.Dir(cfg.Scope.DotClaudeDir)
+	if !cfg.Scope.InProject() {
+		if projectRoot, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+	files, err := tpl.Render(templates.Data{
+		ContextName: slug,
+		ProjectName: filepath.Base(projectRoot),
+		ProjectRoot: projectRoot,
+		Scope:       string(cfg.Scope.Type),
+		Vars:        vars,
+	})
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return report(createOutput{SaveResult: &context.SaveResult{Name: slug, Files: len(files)}, Template: name, DryRun: true}, func() {
+			fmt.Printf("[dry-run] Would create context %q from template %q (%d files)\n", slug, name, len(files))
+		})
+	}
+
+	description := createDescription
+	if description == "" {
+		description = tpl.Description
+	}
+	saveResult, err := context.CreateFromFiles(cfg, slug, description, files)
+	if err != nil {
+		return err
+	}
+
+	// Switch to the new context
+	result, err := context.Restore(context.RestoreOptions{
+		Name:   slug,
+		Force:  true,
+		Config: cfg,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cfg.Scope != nil && cfg.Scope.InProject() {
+		ensureGitignore(cfg.Scope, false)
+	}
+
+	return report(createOutput{SaveResult: saveResult, Template: name}, func() {
+		fmt.Printf("Context %q created from template %q (%d files)\n", slug, name, result.FilesRestored)
+	})
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	gocontext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/client"
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newUICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ui",
+		Short: "Open the full-screen management dashboard",
+		Long: "Browse contexts per scope, view snapshot and backup files, compare live files\n" +
+			"with a saved context, and save, switch or delete contexts.",
+		Args: cobra.NoArgs,
+		RunE: runUI,
+	}
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	if jsonOutput() {
+		return usageErrorf("the dashboard does not support --output json")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	cfgs, err := pickerConfigs(cfg)
+	if err != nil {
+		return err
+	}
+
+	backend := &dashboardBackend{byScope: map[string]*config.Config{}}
+	for _, c := range cfgs {
+		// Prompts would fight the dashboard for the terminal; fall back to
+		// the non-interactive defaults.
+		c.Confirm = nil
+		scope := string(c.Scope.Type)
+		backend.scopes = append(backend.scopes, scope)
+		backend.byScope[scope] = c
+	}
+	return ui.RunDashboard(backend)
+}
+
+// dashboardBackend implements ui.DashboardBackend on top of the context package.
+type dashboardBackend struct {
+	scopes  []string
+	byScope map[string]*config.Config
+}
+
+func (b *dashboardBackend) Scopes() []string { return b.scopes }
+
+func (b *dashboardBackend) Contexts(scope string) ([]ui.PickerItem, error) {
+	return pickerItems(b.byScope[scope])
+}
+
+func (b *dashboardBackend) Backups(scope string) ([]ui.BackupItem, error) {
+	backups, err := context.ListBackups(b.byScope[scope])
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ui.BackupItem, len(backups))
+	for i, bk := range backups {
+		items[i] = ui.BackupItem{Name: bk.Name, CreatedAt: bk.CreatedAt, Paths: bk.Files, TotalSize: bk.TotalSize}
+	}
+	return items, nil
+}
+
+func (b *dashboardBackend) ReadFile(scope string, src ui.FileSource, relPath string) ([]byte, error) {
+	rel := filepath.FromSlash(relPath)
+	if !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("invalid file path %q", relPath)
+	}
+	cfg := b.byScope[scope]
+	base := filepath.Join(cfg.ContextsDir(), context.Slugify(src.Name))
+	if src.Backup {
+		base = filepath.Join(cfg.BackupsDir(), filepath.Base(src.Name))
+	}
+	return os.ReadFile(filepath.Join(base, rel))
+}
+
+func (b *dashboardBackend) client(scope string) *client.Client {
+	return client.New(b.byScope[scope])
+}
+
+func (b *dashboardBackend) Drift(scope, name string) (*ui.DriftView, error) {
+	d, err := b.client(scope).Diff(gocontext.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+	return &ui.DriftView{Added: d.Added, Removed: d.Removed, Modified: d.Modified}, nil
+}
+
+func (b *dashboardBackend) Save(scope, name string, overwrite bool) error {
+	_, err := b.client(scope).Save(gocontext.Background(), client.SaveOptions{Name: name, Overwrite: overwrite})
+	return err
+}
+
+func (b *dashboardBackend) Switch(scope, name string) error {
+	_, err := b.client(scope).Switch(gocontext.Background(), name, client.SwitchOptions{Force: force})
+	return err
+}
+
+func (b *dashboardBackend) Delete(scope, name string) error {
+	_, err := b.client(scope).Delete(gocontext.Background(), name, client.DeleteOptions{})
+	return err
+}
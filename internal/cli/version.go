@@ -14,8 +14,13 @@ func newVersionCmd() *cobra.Command {
 		Use:   "version",
 		Short: "Print version information",
 		Args:  cobra.NoArgs,
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("claudectx %s\n", Version)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := struct {
+				Version string `json:"version"`
+			}{Version}
+			return report(out, func() {
+				fmt.Printf("claudectx %s\n", Version)
+			})
 		},
 	}
 }
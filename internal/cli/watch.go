@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchDebounce time.Duration
+	watchInterval time.Duration
+	watchPoll     bool
+)
+
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Save live edits into the active context as they happen",
+		Long: "Watch the scope's .claude/ directory and extra files (CLAUDE.md, .mcp.json,\n" +
+			"~/.claude.json) and, once changes settle, save them into the active context.\n" +
+			"The previous snapshot is kept as a revision (see 'claudectx revisions').\n" +
+			"Writes made by switching contexts and locked contexts are left alone.\n\n" +
+			"Uses inotify on Linux and polling elsewhere.",
+		Args: cobra.NoArgs,
+		RunE: runWatch,
+	}
+
+	cmd.Flags().DurationVar(&watchDebounce, "debounce", watch.DefaultDebounce, "Wait this long after the last change before saving")
+	cmd.Flags().DurationVar(&watchInterval, "interval", watch.DefaultInterval, "Polling interval when polling")
+	cmd.Flags().BoolVar(&watchPoll, "poll", false, "Poll for changes even where inotify is available")
+
+	return cmd
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if jsonOutput() {
+		return usageErrorf("watch does not support --output json")
+	}
+	if dryRun {
+		return usageErrorf("watch does not support --dry-run")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	// Saves happen unattended.
+	cfg.Confirm = nil
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching %s scope for changes (Ctrl+C to stop)...\n", cfg.Scope.Type)
+	return watch.Run(ctx, watch.Options{
+		Config:   cfg,
+		Debounce: watchDebounce,
+		Interval: watchInterval,
+		Poll:     watchPoll,
+		Report:   printWatchEvent,
+	})
+}
+
+func printWatchEvent(ev watch.Event) {
+	stamp := time.Now().Format("15:04:05")
+	switch {
+	case ev.Err != nil:
+		fmt.Fprintf(os.Stderr, "%s Error saving context %q: %v\n", stamp, ev.Context, ev.Err)
+	case ev.Saved:
+		var parts []string
+		for _, group := range []struct {
+			label string
+			paths []string
+		}{{"added", ev.Drift.Added}, {"modified", ev.Drift.Modified}, {"removed", ev.Drift.Removed}} {
+			if len(group.paths) > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", len(group.paths), group.label))
+			}
+		}
+		fmt.Printf("%s Saved context %q (%s)\n", stamp, ev.Context, strings.Join(parts, ", "))
+		if verbose {
+			for _, p := range slices.Concat(ev.Drift.Added, ev.Drift.Modified, ev.Drift.Removed) {
+				fmt.Printf("  %s\n", p)
+			}
+		}
+	case verbose:
+		fmt.Printf("%s Skipped: %s\n", stamp, ev.Skipped)
+	}
+}
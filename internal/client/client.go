@@ -0,0 +1,228 @@
+// Package client implements the claudectx Client shared by the claudectx
+// command, its API server and the public pkg/claudectx package. A Client is
+// bound to the scope of one loaded config and lists, inspects, saves,
+// switches and deletes the contexts stored there.
+//
+// Methods check their context.Context before each step; a file operation that
+// has already started runs to completion.
+package client
+
+import (
+	gocontext "context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+)
+
+// Client manages the contexts of a single scope.
+type Client struct {
+	cfg *config.Config
+}
+
+// New returns a Client for an already loaded config.
+func New(cfg *config.Config) *Client {
+	return &Client{cfg: cfg}
+}
+
+// Scope returns the client's scope type ("user", "project", "local", "managed" or a custom scope name).
+func (c *Client) Scope() string {
+	return string(c.cfg.Scope.Type)
+}
+
+// StorageDir returns the directory holding the scope's contexts and backups.
+func (c *Client) StorageDir() string {
+	return c.cfg.StorageDir
+}
+
+// Info describes a saved context.
+type Info struct {
+	*context.Manifest
+	Current bool `json:"isCurrent"`
+}
+
+// List returns the saved contexts in name order. Contexts whose manifest
+// cannot be read are skipped.
+func (c *Client) List(ctx gocontext.Context) ([]Info, error) {
+	names, err := context.ListContexts(c.cfg.ContextsDir())
+	if err != nil {
+		return nil, err
+	}
+	current, _ := context.GetCurrent(c.cfg)
+
+	infos := []Info{}
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		m, err := context.ReadManifest(filepath.Join(c.cfg.ContextsDir(), name))
+		if err != nil {
+			continue
+		}
+		infos = append(infos, Info{Manifest: m, Current: name == current})
+	}
+	return infos, nil
+}
+
+// Show returns a saved context.
+func (c *Client) Show(ctx gocontext.Context, name string) (*Info, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	slug := context.Slugify(name)
+	m, err := context.ReadManifest(filepath.Join(c.cfg.ContextsDir(), slug))
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+	current, _ := context.GetCurrent(c.cfg)
+	return &Info{Manifest: m, Current: slug == current}, nil
+}
+
+// Current returns the name of the active context, or "" if none is active.
+func (c *Client) Current(ctx gocontext.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return context.GetCurrent(c.cfg)
+}
+
+// SaveOptions configures Client.Save.
+type SaveOptions struct {
+	Name         string
+	Description  string
+	Overwrite    bool // replace an existing, unlocked context
+	KeepRevision bool // keep the overwritten snapshot as a revision
+	DryRun       bool
+	Verbose      bool
+}
+
+// Save snapshots the live files as a context, which becomes the active one.
+// When creating a new context, the previously active context is auto-saved
+// first so its edits aren't lost.
+func (c *Client) Save(ctx gocontext.Context, opts SaveOptions) (*context.SaveResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !opts.Overwrite && !opts.DryRun {
+		context.AutoSaveCurrent(c.cfg, context.Slugify(opts.Name))
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return context.Save(context.SaveOptions{
+		Name:         opts.Name,
+		Description:  opts.Description,
+		Overwrite:    opts.Overwrite,
+		KeepRevision: opts.KeepRevision,
+		DryRun:       opts.DryRun,
+		Verbose:      opts.Verbose,
+		Config:       c.cfg,
+	})
+}
+
+// SwitchOptions configures Client.Switch.
+type SwitchOptions struct {
+	DryRun  bool
+	Force   bool // switch even if already active; skip failed backups, validation and uncommitted-change checks
+	Verbose bool
+
+	// Tracked overrides the config's handling of git-tracked files in
+	// project scope: "manage", "skip" or "skip-worktree".
+	Tracked string
+}
+
+// SwitchResult is the result of Client.Switch.
+type SwitchResult struct {
+	*context.RestoreResult
+	Previous      string `json:"previous,omitempty"`
+	AlreadyActive bool   `json:"alreadyActive,omitempty"`
+	DryRun        bool   `json:"dryRun,omitempty"`
+}
+
+// Switch makes name the active context: the live files are backed up and
+// replaced with the snapshot. Switching to the active context is a no-op
+// unless opts.Force is set.
+func (c *Client) Switch(ctx gocontext.Context, name string, opts SwitchOptions) (*SwitchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	slug := context.Slugify(name)
+	if !context.ContextExists(c.cfg.ContextsDir(), slug) {
+		return nil, fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+
+	current, _ := context.GetCurrent(c.cfg)
+	if current == slug && !opts.Force {
+		return &SwitchResult{RestoreResult: &context.RestoreResult{Name: slug}, Previous: current, AlreadyActive: true}, nil
+	}
+
+	result, err := context.Restore(context.RestoreOptions{
+		Name:    name,
+		DryRun:  opts.DryRun,
+		Force:   opts.Force,
+		Verbose: opts.Verbose,
+		Config:  c.cfg,
+		Tracked: opts.Tracked,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SwitchResult{RestoreResult: result, Previous: current, DryRun: opts.DryRun}, nil
+}
+
+// DeleteOptions configures Client.Delete.
+type DeleteOptions struct {
+	DryRun bool
+}
+
+// DeleteResult is the result of Client.Delete. Deleted is false for a dry run
+// or when the config's Confirm declined; DryRun is only set if it was not declined.
+type DeleteResult struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+}
+
+// Delete removes a saved context. The active context cannot be deleted.
+func (c *Client) Delete(ctx gocontext.Context, name string, opts DeleteOptions) (*DeleteResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	slug := context.Slugify(name)
+	if !context.ContextExists(c.cfg.ContextsDir(), slug) {
+		return nil, fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+	if current, _ := context.GetCurrent(c.cfg); current == slug {
+		return nil, fmt.Errorf("context %q is %w; switch to another context first", slug, context.ErrActive)
+	}
+
+	result := &DeleteResult{Name: slug}
+	if c.cfg.Confirm != nil && !c.cfg.Confirm(fmt.Sprintf("Delete context %q?", slug)) {
+		return result, nil
+	}
+	if opts.DryRun {
+		result.DryRun = true
+		return result, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := context.DeleteContext(c.cfg, slug); err != nil {
+		return nil, err
+	}
+	result.Deleted = true
+	return result, nil
+}
+
+// Diff compares the live files with a saved context.
+func (c *Client) Diff(ctx gocontext.Context, name string) (*context.DriftResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	slug := context.Slugify(name)
+	if !context.ContextExists(c.cfg.ContextsDir(), slug) {
+		return nil, fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+	return context.Drift(c.cfg, slug)
+}
@@ -0,0 +1,36 @@
+package config
+
+// Actions taken when auto-save detects that the live files look broken
+// compared to the saved snapshot.
+const (
+	AutoSavePrompt    = "prompt"    // ask before overwriting; skip if declined
+	AutoSaveRevision  = "revision"  // keep the old snapshot as a revision, then overwrite
+	AutoSaveSkip      = "skip"      // leave the saved snapshot untouched
+	AutoSaveOverwrite = "overwrite" // overwrite without safeguards
+)
+
+// DefaultMaxRevisions is the number of revisions kept per context.
+const DefaultMaxRevisions = 10
+
+// AutoSavePolicy controls how AutoSaveCurrent protects the active context.
+type AutoSavePolicy struct {
+	OnSuspicious string `json:"onSuspicious,omitempty"` // one of the AutoSave* actions; default "revision"
+	MaxRevisions int    `json:"maxRevisions,omitempty"` // revisions kept per context; default 10
+}
+
+// Action returns the configured action for suspicious changes.
+func (p AutoSavePolicy) Action() string {
+	switch p.OnSuspicious {
+	case AutoSavePrompt, AutoSaveSkip, AutoSaveOverwrite:
+		return p.OnSuspicious
+	}
+	return AutoSaveRevision
+}
+
+// RevisionLimit returns the number of revisions to keep per context.
+func (p AutoSavePolicy) RevisionLimit() int {
+	if p.MaxRevisions > 0 {
+		return p.MaxRevisions
+	}
+	return DefaultMaxRevisions
+}
@@ -2,25 +2,35 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 )
 
 // Config holds user configuration for claudectx.
 type Config struct {
-	StorageDir      string   `json:"storageDir,omitempty"`
-	IncludePatterns []string `json:"includePatterns,omitempty"`
-	ExcludePatterns []string `json:"excludePatterns,omitempty"`
-	Scope           *Scope   `json:"-"` // runtime only, set by LoadWithScope
+	StorageDir      string             `json:"storageDir,omitempty"`
+	IncludePatterns []string           `json:"includePatterns,omitempty"`
+	ExcludePatterns []string           `json:"excludePatterns,omitempty"`
+	Hooks           Hooks              `json:"hooks,omitempty"`
+	AutoSave        AutoSavePolicy     `json:"autoSave,omitempty"`
+	Validation      ValidationPolicy   `json:"validation,omitempty"`
+	Git             GitPolicy          `json:"git,omitempty"`
+	Worktrees       string             `json:"worktrees,omitempty"`
+	Profiles        map[string]Profile `json:"profiles,omitempty"`
+	Scope           *Scope             `json:"-"` // runtime only, set by LoadWithScope
+
+	// Confirm asks the user a yes/no question. Runtime only, set by the CLI;
+	// nil means non-interactive and callers fall back to a safe default.
+	Confirm func(question string) bool `json:"-"`
 }
 
-// DefaultStorageDir returns the default ~/.claudectx/ path.
+// DefaultStorageDir returns the user-scope storage directory, ~/.claudectx/
+// unless overridden (see ResolveStorageDir).
 func DefaultStorageDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".claudectx"), nil
+	r, err := ResolveStorageDir()
+	return r.Path, err
 }
 
 // Load reads config from the given path, falling back to defaults.
@@ -43,7 +53,7 @@ func LoadWithScope(path string, scope *Scope) (*Config, error) {
 	}
 
 	if path == "" {
-		path = filepath.Join(scope.StorageDir, "config.json")
+		path = scope.ConfigFile()
 	}
 
 	data, err := os.ReadFile(path)
@@ -55,7 +65,7 @@ func LoadWithScope(path string, scope *Scope) (*Config, error) {
 	}
 
 	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidConfig, path, err)
 	}
 
 	// Re-apply defaults for any empty fields
@@ -68,6 +78,7 @@ func LoadWithScope(path string, scope *Scope) (*Config, error) {
 	if cfg.StorageDir == "" {
 		cfg.StorageDir = scope.StorageDir
 	}
+	cfg.ExcludePatterns = withReserved(cfg.ExcludePatterns, scope.ReservedPatterns)
 
 	// Always keep scope reference
 	cfg.Scope = scope
@@ -75,17 +86,55 @@ func LoadWithScope(path string, scope *Scope) (*Config, error) {
 	return cfg, nil
 }
 
+// withReserved returns excludes extended with any reserved patterns it lacks,
+// so configured patterns can never pull another scope's files in.
+func withReserved(excludes, reserved []string) []string {
+	out := excludes
+	for _, p := range reserved {
+		if !slices.Contains(out, p) {
+			out = append(slices.Clip(out), p)
+		}
+	}
+	return out
+}
+
 // ContextsDir returns the path to the contexts directory.
 func (c *Config) ContextsDir() string {
 	return filepath.Join(c.StorageDir, "contexts")
 }
 
+// StateDir returns the directory holding per-checkout state: the active
+// marker and backups. It differs from StorageDir only for linked git
+// worktrees sharing the main worktree's contexts.
+func (c *Config) StateDir() string {
+	if c.Scope != nil && c.Scope.StateDir != "" {
+		return c.Scope.StateDir
+	}
+	return c.StorageDir
+}
+
 // BackupsDir returns the path to the backups directory.
 func (c *Config) BackupsDir() string {
-	return filepath.Join(c.StorageDir, "backups")
+	return filepath.Join(c.StateDir(), "backups")
+}
+
+// ProfileBackupsDir returns the path to the directory holding the combined
+// backups taken by 'profile use', kept apart from the pre-switch backups.
+func (c *Config) ProfileBackupsDir() string {
+	return filepath.Join(c.StateDir(), "profile-backups")
+}
+
+// TemplatesDir returns the path to the user-provided templates directory.
+func (c *Config) TemplatesDir() string {
+	return filepath.Join(c.StorageDir, "templates")
+}
+
+// RevisionsDir returns the path to the directory holding previous snapshots.
+func (c *Config) RevisionsDir() string {
+	return filepath.Join(c.StorageDir, "revisions")
 }
 
 // CurrentFile returns the path to the 'current' marker file.
 func (c *Config) CurrentFile() string {
-	return filepath.Join(c.StorageDir, "current")
+	return filepath.Join(c.StateDir(), "current")
 }
@@ -47,7 +47,45 @@ func TestConfigPaths(t *testing.T) {
 	if cfg.BackupsDir() != "/tmp/claudectx/backups" {
 		t.Errorf("unexpected backups dir: %s", cfg.BackupsDir())
 	}
+	if cfg.ProfileBackupsDir() != "/tmp/claudectx/profile-backups" {
+		t.Errorf("unexpected profile backups dir: %s", cfg.ProfileBackupsDir())
+	}
+	if cfg.TemplatesDir() != "/tmp/claudectx/templates" {
+		t.Errorf("unexpected templates dir: %s", cfg.TemplatesDir())
+	}
 	if cfg.CurrentFile() != "/tmp/claudectx/current" {
 		t.Errorf("unexpected current file: %s", cfg.CurrentFile())
 	}
 }
+
+func TestLoadHooks(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.json")
+	err := os.WriteFile(cfgPath, []byte(`{
+		"hooks": {
+			"preSwitch": "check-switch",
+			"postSwitch": ["notify", "refresh"],
+			"timeoutSeconds": 5
+		}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Hooks.PreSwitch) != 1 || cfg.Hooks.PreSwitch[0] != "check-switch" {
+		t.Errorf("unexpected preSwitch hooks: %v", cfg.Hooks.PreSwitch)
+	}
+	if len(cfg.Hooks.PostSwitch) != 2 {
+		t.Errorf("unexpected postSwitch hooks: %v", cfg.Hooks.PostSwitch)
+	}
+	if cfg.Hooks.Timeout().Seconds() != 5 {
+		t.Errorf("unexpected timeout: %v", cfg.Hooks.Timeout())
+	}
+	if (Hooks{}).Timeout() != DefaultHookTimeout {
+		t.Error("expected default hook timeout")
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// scopeNameRe matches valid custom scope names.
+var scopeNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// builtinScopes are the scope names custom scopes may not use.
+var builtinScopes = []string{string(ScopeUser), string(ScopeProject), string(ScopeLocal), string(ScopeManaged)}
+
+// ScopeDefinition declares a custom scope in the user config under "scopes".
+// Paths may start with ~ and contain $VARS; after expansion they must be
+// absolute. Either Patterns or IncludePatterns must be set.
+type ScopeDefinition struct {
+	DotClaudeDir    string      `json:"dotClaudeDir"`
+	ExtraFiles      []ExtraFile `json:"extraFiles,omitempty"`
+	StorageDir      string      `json:"storageDir,omitempty"` // default ~/.claudectx/scopes/<name>
+	Patterns        string      `json:"patterns,omitempty"`   // "user" or "project" pattern preset
+	IncludePatterns []string    `json:"includePatterns,omitempty"`
+	ExcludePatterns []string    `json:"excludePatterns,omitempty"`
+}
+
+// scopePatternPresets are the include and exclude patterns a custom scope
+// can start from. "user" suits a second Claude config dir; "project" takes
+// every file except OS junk.
+var scopePatternPresets = map[string][2][]string{
+	"user":    {DefaultIncludePatterns, DefaultExcludePatterns},
+	"project": {DefaultProjectIncludePatterns, {"**/.DS_Store", "**/Thumbs.db", "**/desktop.ini"}},
+}
+
+// CustomScope builds the custom scope named name from the user config.
+// It returns ErrInvalidScope if no such scope is declared.
+func CustomScope(name string) (*Scope, error) {
+	home, err := ResolveHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(home.Path, "config.json")
+	defs, err := readScopeDefinitions(path)
+	if err != nil {
+		return nil, err
+	}
+	def, ok := defs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q: must be %s or a scope declared in %s", ErrInvalidScope, name, scopeNameList(), path)
+	}
+	scope, err := def.build(name, home.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: scope %q: %w", ErrInvalidConfig, path, name, err)
+	}
+	source := path + " scopes." + name
+	scope.Sources = map[string]string{"dotClaudeDir": source, "storageDir": source}
+	if def.StorageDir == "" {
+		scope.Sources["storageDir"] = home.Source
+	}
+	for _, ef := range scope.ExtraFiles {
+		scope.Sources[ef.Tag] = source
+	}
+	return scope, nil
+}
+
+func scopeNameList() string {
+	quoted := make([]string, len(builtinScopes))
+	for i, s := range builtinScopes {
+		quoted[i] = "'" + s + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func readScopeDefinitions(path string) (map[string]ScopeDefinition, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Scopes map[string]ScopeDefinition `json:"scopes"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidConfig, path, err)
+	}
+	return cfg.Scopes, nil
+}
+
+// build validates the definition and turns it into a Scope.
+func (d ScopeDefinition) build(name, homeDir string) (*Scope, error) {
+	if slices.Contains(builtinScopes, name) {
+		return nil, fmt.Errorf("name is reserved for a built-in scope")
+	}
+	if !scopeNameRe.MatchString(name) {
+		return nil, fmt.Errorf("name must be lowercase letters, digits and dashes")
+	}
+	if d.DotClaudeDir == "" {
+		return nil, fmt.Errorf("dotClaudeDir is required")
+	}
+	dotClaudeDir, err := expandPath(d.DotClaudeDir)
+	if err != nil {
+		return nil, err
+	}
+	storageDir := filepath.Join(homeDir, "scopes", name)
+	if d.StorageDir != "" {
+		if storageDir, err = expandPath(d.StorageDir); err != nil {
+			return nil, err
+		}
+	}
+
+	tags := map[string]bool{}
+	bases := map[string]bool{}
+	var extraFiles []ExtraFile
+	for _, ef := range d.ExtraFiles {
+		if ef.Tag == "" || ef.Tag == "dotclaude" || tags[ef.Tag] {
+			return nil, fmt.Errorf("extra file %q: tag must be unique and not %q", ef.Path, "dotclaude")
+		}
+		path, err := expandPath(ef.Path)
+		if err != nil {
+			return nil, err
+		}
+		// Extra files are stored under their base name.
+		base := filepath.Base(path)
+		if bases[base] || base == "dotclaude" || base == "manifest.json" {
+			return nil, fmt.Errorf("extra file %q: base name %q is already used", ef.Path, base)
+		}
+		tags[ef.Tag], bases[base] = true, true
+		extraFiles = append(extraFiles, ExtraFile{Path: path, Tag: ef.Tag})
+	}
+
+	// Explicit patterns replace the preset's.
+	var include, exclude []string
+	if d.Patterns != "" {
+		preset, ok := scopePatternPresets[d.Patterns]
+		if !ok {
+			return nil, fmt.Errorf("patterns %q: must be 'user' or 'project'", d.Patterns)
+		}
+		include, exclude = preset[0], preset[1]
+	} else if len(d.IncludePatterns) == 0 {
+		return nil, fmt.Errorf("patterns ('user' or 'project') or includePatterns is required")
+	}
+	if len(d.IncludePatterns) > 0 {
+		include = d.IncludePatterns
+	}
+	if len(d.ExcludePatterns) > 0 {
+		exclude = d.ExcludePatterns
+	}
+
+	return &Scope{
+		Type:            ScopeType(name),
+		DotClaudeDir:    dotClaudeDir,
+		ExtraFiles:      extraFiles,
+		StorageDir:      storageDir,
+		IncludePatterns: include,
+		ExcludePatterns: exclude,
+	}, nil
+}
+
+// expandPath expands ~ and environment variables and requires the result to
+// be absolute.
+func expandPath(path string) (string, error) {
+	expanded := os.ExpandEnv(path)
+	if expanded == "~" || strings.HasPrefix(expanded, "~/") || strings.HasPrefix(expanded, `~\`) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		expanded = filepath.Join(home, expanded[1:])
+	}
+	if !filepath.IsAbs(expanded) {
+		return "", fmt.Errorf("path %q must be absolute", path)
+	}
+	return filepath.Clean(expanded), nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeUserConfig points HOME at a temp dir with the given user config.
+func writeUserConfig(t *testing.T, content string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(HomeDirEnv, "")
+	t.Setenv(StorageDirEnv, "")
+	os.MkdirAll(filepath.Join(home, ".claudectx"), 0755)
+	os.WriteFile(filepath.Join(home, ".claudectx", "config.json"), []byte(content), 0644)
+	return home
+}
+
+func TestCustomScope(t *testing.T) {
+	home := writeUserConfig(t, `{
+		"scopes": {
+			"alt": {
+				"dotClaudeDir": "~/.claude-alt",
+				"extraFiles": [{"path": "$HOME/.claude-alt.json", "tag": "claudejson"}],
+				"includePatterns": ["settings.json"]
+			},
+			"second": {
+				"dotClaudeDir": "~/.claude-second",
+				"patterns": "user"
+			},
+			"prompts": {
+				"dotClaudeDir": "/srv/team/prompts",
+				"storageDir": "/srv/team/.claudectx",
+				"patterns": "project"
+			}
+		}
+	}`)
+
+	scope, err := ResolveScope("alt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.Type != ScopeType("alt") {
+		t.Errorf("Type = %s, want alt", scope.Type)
+	}
+	if scope.DotClaudeDir != filepath.Join(home, ".claude-alt") {
+		t.Errorf("DotClaudeDir = %s", scope.DotClaudeDir)
+	}
+	if ef := scope.ExtraFileByTag("claudejson"); ef == nil || ef.Path != filepath.Join(home, ".claude-alt.json") {
+		t.Errorf("extra files = %+v", scope.ExtraFiles)
+	}
+	if scope.StorageDir != filepath.Join(home, ".claudectx", "scopes", "alt") {
+		t.Errorf("StorageDir = %s, want default under ~/.claudectx/scopes", scope.StorageDir)
+	}
+	if len(scope.IncludePatterns) != 1 || len(scope.ExcludePatterns) != 0 {
+		t.Errorf("patterns = %v / %v", scope.IncludePatterns, scope.ExcludePatterns)
+	}
+
+	scope, err = ResolveScope("second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(scope.IncludePatterns, DefaultIncludePatterns) || !slices.Equal(scope.ExcludePatterns, DefaultExcludePatterns) {
+		t.Errorf("user preset patterns = %v / %v", scope.IncludePatterns, scope.ExcludePatterns)
+	}
+
+	scope, err = ResolveScope("prompts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.StorageDir != "/srv/team/.claudectx" {
+		t.Errorf("StorageDir = %s", scope.StorageDir)
+	}
+	if slices.Contains(scope.ExcludePatterns, "settings.local.json") {
+		t.Errorf("project preset excludes local settings: %v", scope.ExcludePatterns)
+	}
+
+	if _, err := ResolveScope("missing"); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("undeclared scope: expected ErrInvalidScope, got %v", err)
+	}
+	if _, err := ResolveScopeWithRoot("alt", t.TempDir()); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("with --root: expected ErrInvalidScope, got %v", err)
+	}
+}
+
+func TestCustomScopeInvalid(t *testing.T) {
+	tests := []struct {
+		name, scope, config string
+	}{
+		{"reserved name", "user", `{"scopes": {"user": {"dotClaudeDir": "/tmp/x"}}}`},
+		{"bad name", "Bad Name", `{"scopes": {"Bad Name": {"dotClaudeDir": "/tmp/x"}}}`},
+		{"missing dir", "x", `{"scopes": {"x": {"patterns": "user"}}}`},
+		{"missing patterns", "x", `{"scopes": {"x": {"dotClaudeDir": "/tmp/x"}}}`},
+		{"unknown preset", "x", `{"scopes": {"x": {"dotClaudeDir": "/tmp/x", "patterns": "all"}}}`},
+		{"relative dir", "x", `{"scopes": {"x": {"dotClaudeDir": "rel/dir", "patterns": "user"}}}`},
+		{"dotclaude tag", "x", `{"scopes": {"x": {"dotClaudeDir": "/tmp/x", "patterns": "user", "extraFiles": [{"path": "/tmp/a", "tag": "dotclaude"}]}}}`},
+		{"duplicate tag", "x", `{"scopes": {"x": {"dotClaudeDir": "/tmp/x", "patterns": "user", "extraFiles": [{"path": "/tmp/a", "tag": "t"}, {"path": "/tmp/b", "tag": "t"}]}}}`},
+		{"duplicate base name", "x", `{"scopes": {"x": {"dotClaudeDir": "/tmp/x", "patterns": "user", "extraFiles": [{"path": "/tmp/a/f", "tag": "a"}, {"path": "/tmp/b/f", "tag": "b"}]}}}`},
+		{"unparsable config", "x", `{"scopes": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeUserConfig(t, tt.config)
+			if _, err := CustomScope(tt.scope); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+
+	// Built-in names always resolve to the built-in scope.
+	writeUserConfig(t, tests[0].config)
+	if scope, err := ResolveScope("user"); err != nil || scope.Type != ScopeUser {
+		t.Errorf("user: %v, %v", scope, err)
+	}
+}
@@ -33,5 +33,15 @@ var DefaultExcludePatterns = []string{
 // DefaultProjectIncludePatterns include all files for project-scope snapshots.
 var DefaultProjectIncludePatterns = []string{"**"}
 
-// DefaultProjectExcludePatterns exclude OS junk from project-scope snapshots.
-var DefaultProjectExcludePatterns = []string{"**/.DS_Store", "**/Thumbs.db", "**/desktop.ini"}
+// DefaultProjectExcludePatterns exclude OS junk and personal local settings
+// (managed by the local scope) from project-scope snapshots. The local
+// settings stay excluded even when a config replaces these patterns; see
+// Scope.ReservedPatterns.
+var DefaultProjectExcludePatterns = []string{"**/.DS_Store", "**/Thumbs.db", "**/desktop.ini", "settings.local.json"}
+
+// DefaultLocalIncludePatterns select the personal settings in .claude/ for
+// local-scope snapshots.
+var DefaultLocalIncludePatterns = []string{"settings.local.json"}
+
+// DefaultLocalExcludePatterns are empty: local scope includes only known files.
+var DefaultLocalExcludePatterns = []string{}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/claude"
+)
+
+// Environment variables relocating claudectx's own files.
+const (
+	HomeDirEnv    = "CLAUDECTX_HOME"        // replaces ~/.claudectx/ (user config and, by default, user storage)
+	StorageDirEnv = "CLAUDECTX_STORAGE_DIR" // user-scope storage only
+)
+
+// envDir returns the absolute value of the environment variable name, if set.
+func envDir(name string) (string, bool, error) {
+	dir := os.Getenv(name)
+	if dir == "" {
+		return "", false, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", name, err)
+	}
+	return abs, true, nil
+}
+
+// ResolveHomeDir returns claudectx's home directory, which holds the user
+// config: $CLAUDECTX_HOME if set, otherwise ~/.claudectx/.
+func ResolveHomeDir() (claude.ResolvedPath, error) {
+	if dir, ok, err := envDir(HomeDirEnv); ok || err != nil {
+		return claude.ResolvedPath{Path: dir, Source: "$" + HomeDirEnv}, err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return claude.ResolvedPath{}, err
+	}
+	return claude.ResolvedPath{Path: filepath.Join(home, ".claudectx"), Source: claude.SourceDefault}, nil
+}
+
+// ResolveStorageDir returns the user-scope storage directory:
+// $CLAUDECTX_STORAGE_DIR if set, otherwise the home directory.
+func ResolveStorageDir() (claude.ResolvedPath, error) {
+	if dir, ok, err := envDir(StorageDirEnv); ok || err != nil {
+		return claude.ResolvedPath{Path: dir, Source: "$" + StorageDirEnv}, err
+	}
+	return ResolveHomeDir()
+}
+
+// UserConfigPath returns the user config file, <home>/config.json. It also
+// holds the settings that apply across scopes ("worktrees", "scopes").
+func UserConfigPath() (string, error) {
+	home, err := ResolveHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home.Path, "config.json"), nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/claude"
+)
+
+func TestStorageDirEnv(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(claude.ConfigDirEnv, "")
+	t.Setenv(HomeDirEnv, "")
+	t.Setenv(StorageDirEnv, "")
+
+	scope, err := UserScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(home, ".claudectx"); scope.StorageDir != want || scope.Sources["storageDir"] != claude.SourceDefault {
+		t.Errorf("default StorageDir = %s (%s), want %s", scope.StorageDir, scope.Sources["storageDir"], want)
+	}
+
+	// CLAUDECTX_HOME moves both the user config and the storage.
+	ctxHome := filepath.Join(home, "ctx")
+	t.Setenv(HomeDirEnv, ctxHome)
+	scope, err = UserScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.StorageDir != ctxHome || scope.ConfigFile() != filepath.Join(ctxHome, "config.json") {
+		t.Errorf("with %s: StorageDir = %s, config = %s", HomeDirEnv, scope.StorageDir, scope.ConfigFile())
+	}
+	if scope.Sources["storageDir"] != "$"+HomeDirEnv {
+		t.Errorf("storageDir source = %q", scope.Sources["storageDir"])
+	}
+
+	// CLAUDECTX_STORAGE_DIR moves only the storage.
+	storage := filepath.Join(home, "storage")
+	t.Setenv(StorageDirEnv, storage)
+	scope, err = UserScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.StorageDir != storage || scope.ConfigFile() != filepath.Join(ctxHome, "config.json") {
+		t.Errorf("with %s: StorageDir = %s, config = %s", StorageDirEnv, scope.StorageDir, scope.ConfigFile())
+	}
+	if scope.Sources["storageDir"] != "$"+StorageDirEnv {
+		t.Errorf("storageDir source = %q", scope.Sources["storageDir"])
+	}
+
+	// The user config is read from CLAUDECTX_HOME, not the storage dir.
+	os.MkdirAll(ctxHome, 0755)
+	os.WriteFile(filepath.Join(ctxHome, "config.json"), []byte(`{"includePatterns": ["CLAUDE.md"]}`), 0644)
+	cfg, err := LoadWithScope("", scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.IncludePatterns) != 1 || cfg.StorageDir != storage {
+		t.Errorf("LoadWithScope: IncludePatterns = %v, StorageDir = %s", cfg.IncludePatterns, cfg.StorageDir)
+	}
+}
+
+func TestConfigDirEnvUserScope(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Setenv(claude.ConfigDirEnv, dir)
+
+	scope, err := UserScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.DotClaudeDir != dir || scope.Sources["dotClaudeDir"] != claude.SourceEnv {
+		t.Errorf("DotClaudeDir = %s (%s), want %s", scope.DotClaudeDir, scope.Sources["dotClaudeDir"], dir)
+	}
+	if ef := scope.ExtraFileByTag("claudejson"); ef == nil || ef.Path != filepath.Join(dir, ".claude.json") {
+		t.Errorf("claudejson = %+v", ef)
+	}
+}
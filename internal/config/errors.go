@@ -0,0 +1,10 @@
+package config
+
+import "errors"
+
+// Sentinel errors wrapped by scope resolution and config loading. Test for
+// them with errors.Is.
+var (
+	ErrInvalidScope  = errors.New("invalid scope")
+	ErrInvalidConfig = errors.New("invalid config")
+)
@@ -0,0 +1,40 @@
+package config
+
+// What Restore does in project scope when a managed file tracked by git has
+// uncommitted changes that the switch would overwrite or remove.
+const (
+	GitModifiedWarn   = "warn"   // print the files and continue
+	GitModifiedAbort  = "abort"  // refuse the switch unless forced
+	GitModifiedIgnore = "ignore" // don't check
+)
+
+// How Restore treats managed files tracked by git in project scope.
+const (
+	GitTrackedManage       = "manage"        // rewrite tracked files like any other
+	GitTrackedSkip         = "skip"          // only manage untracked and ignored files
+	GitTrackedSkipWorktree = "skip-worktree" // rewrite, then hide them with git update-index --skip-worktree
+)
+
+// GitPolicy controls how switching interacts with files tracked by git.
+type GitPolicy struct {
+	OnModified string `json:"onModified,omitempty"` // one of the GitModified* actions; default "warn"
+	Tracked    string `json:"tracked,omitempty"`    // one of the GitTracked* modes; default "manage"
+}
+
+// ModifiedAction returns the configured action for modified tracked files.
+func (p GitPolicy) ModifiedAction() string {
+	switch p.OnModified {
+	case GitModifiedAbort, GitModifiedIgnore:
+		return p.OnModified
+	}
+	return GitModifiedWarn
+}
+
+// TrackedMode returns the configured handling of tracked files.
+func (p GitPolicy) TrackedMode() string {
+	switch p.Tracked {
+	case GitTrackedSkip, GitTrackedSkipWorktree:
+		return p.Tracked
+	}
+	return GitTrackedManage
+}
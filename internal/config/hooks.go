@@ -0,0 +1,51 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DefaultHookTimeout bounds how long a single hook command may run.
+const DefaultHookTimeout = 30 * time.Second
+
+// Hooks lists shell commands run around context operations. A non-zero exit
+// from a pre* hook vetoes the operation; post* hook failures only warn.
+type Hooks struct {
+	PreSave        HookCommands `json:"preSave,omitempty"`
+	PostSave       HookCommands `json:"postSave,omitempty"`
+	PreSwitch      HookCommands `json:"preSwitch,omitempty"`
+	PostSwitch     HookCommands `json:"postSwitch,omitempty"`
+	PreDelete      HookCommands `json:"preDelete,omitempty"`
+	TimeoutSeconds int          `json:"timeoutSeconds,omitempty"`
+}
+
+// HookCommands is a list of shell commands. In config.json it may be written
+// as a single string or an array of strings.
+type HookCommands []string
+
+// UnmarshalJSON accepts either a string or an array of strings.
+func (h *HookCommands) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single == "" {
+			*h = nil
+		} else {
+			*h = HookCommands{single}
+		}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*h = list
+	return nil
+}
+
+// Timeout returns the configured per-command timeout, or DefaultHookTimeout.
+func (h Hooks) Timeout() time.Duration {
+	if h.TimeoutSeconds > 0 {
+		return time.Duration(h.TimeoutSeconds) * time.Second
+	}
+	return DefaultHookTimeout
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pfldy2850/claudectx/internal/claude"
+)
+
+// DefaultManagedIncludePatterns are the policy files Claude Code reads from
+// the managed settings directory.
+var DefaultManagedIncludePatterns = []string{"managed-settings.json", "managed-mcp.json"}
+
+// DefaultManagedExcludePatterns are empty: managed scope includes only known files.
+var DefaultManagedExcludePatterns = []string{}
+
+// ManagedSettingsDir returns the system directory Claude Code reads managed
+// policy settings from.
+func ManagedSettingsDir() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "/Library/Application Support/ClaudeCode"
+	case "windows":
+		return filepath.Join(programData(), "ClaudeCode")
+	default:
+		return "/etc/claude-code"
+	}
+}
+
+// ManagedStorageDir returns the system-wide directory where managed policy
+// contexts are stored, shared by all users of the machine.
+func ManagedStorageDir() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "/Library/Application Support/claudectx"
+	case "windows":
+		return filepath.Join(programData(), "claudectx")
+	default:
+		return "/var/lib/claudectx"
+	}
+}
+
+// geteuid is replaced in tests.
+var geteuid = os.Geteuid
+
+func programData() string {
+	if dir := os.Getenv("ProgramData"); dir != "" {
+		return dir
+	}
+	return `C:\ProgramData`
+}
+
+// ManagedScope builds a Scope for the machine-wide managed policy settings.
+// It is only used when requested explicitly with --scope managed.
+func ManagedScope() *Scope {
+	return ManagedScopeAt(ManagedSettingsDir(), ManagedStorageDir())
+}
+
+// ManagedScopeAt builds a managed Scope for the given settings and storage
+// directories (no detection).
+func ManagedScopeAt(settingsDir, storageDir string) *Scope {
+	return &Scope{
+		Type:            ScopeManaged,
+		DotClaudeDir:    settingsDir,
+		StorageDir:      storageDir,
+		IncludePatterns: DefaultManagedIncludePatterns,
+		ExcludePatterns: DefaultManagedExcludePatterns,
+		Sources:         map[string]string{"dotClaudeDir": claude.SourceDefault, "storageDir": claude.SourceDefault},
+	}
+}
+
+// CheckWritable verifies that the scope's live files and storage can be
+// changed by the current user. Only the managed scope, whose directories
+// usually belong to root, is checked; other scopes always pass.
+func (s *Scope) CheckWritable() error {
+	if s.Type != ScopeManaged || geteuid() == 0 {
+		return nil
+	}
+	for _, dir := range []string{s.DotClaudeDir, s.StorageDir} {
+		if err := checkDirWritable(dir); err != nil {
+			return fmt.Errorf("managed scope: %w; run as root (e.g. with sudo) or make it writable", err)
+		}
+	}
+	return nil
+}
+
+// checkDirWritable tries to create a file in dir, or in its nearest existing
+// ancestor if dir does not exist yet.
+func checkDirWritable(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		dir = parent
+	}
+	f, err := os.CreateTemp(dir, ".claudectx-access-*")
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return &fs.PathError{Op: "write", Path: dir, Err: fs.ErrPermission}
+		}
+		return err
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManagedScopeAt(t *testing.T) {
+	scope := ManagedScopeAt("/etc/claude-code", "/var/lib/claudectx")
+
+	if scope.Type != ScopeManaged {
+		t.Errorf("expected managed scope, got %s", scope.Type)
+	}
+	if scope.InProject() {
+		t.Error("managed scope must not be a project scope")
+	}
+	if scope.DotClaudeDir != "/etc/claude-code" || scope.StorageDir != "/var/lib/claudectx" {
+		t.Errorf("unexpected dirs: %s, %s", scope.DotClaudeDir, scope.StorageDir)
+	}
+	if len(scope.ExtraFiles) != 0 {
+		t.Errorf("expected no extra files, got %v", scope.ExtraFiles)
+	}
+}
+
+func TestResolveScopeManaged(t *testing.T) {
+	scope, err := ResolveScope("managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope.Type != ScopeManaged || scope.DotClaudeDir != ManagedSettingsDir() {
+		t.Errorf("got %s scope at %s", scope.Type, scope.DotClaudeDir)
+	}
+
+	if _, err := ResolveScopeWithRoot("managed", t.TempDir()); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("expected ErrInvalidScope with --root, got %v", err)
+	}
+
+	// Never auto-detected.
+	tmp := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmp)
+	if scope, err := ResolveScope(""); err != nil || scope.Type == ScopeManaged {
+		t.Errorf("auto-detected %v, %v", scope, err)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	defer func(f func() int) { geteuid = f }(geteuid)
+	geteuid = func() int { return 1000 } // check the directories even as root
+
+	tmp := t.TempDir()
+	settings := filepath.Join(tmp, "claude-code")
+	os.Mkdir(settings, 0755)
+
+	// Storage that doesn't exist yet is checked through its parent.
+	scope := ManagedScopeAt(settings, filepath.Join(tmp, "lib", "claudectx"))
+	if err := scope.CheckWritable(); err != nil {
+		t.Errorf("writable dirs: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "lib")); !os.IsNotExist(err) {
+		t.Error("the check must not create directories")
+	}
+
+	// Other scopes are never checked.
+	project := ProjectScopeAt(settings)
+	if err := project.CheckWritable(); err != nil {
+		t.Errorf("project scope: %v", err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	os.Chmod(settings, 0555)
+	defer os.Chmod(settings, 0755)
+	if err := scope.CheckWritable(); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected permission error, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Profile switches several scopes at once, set under "profiles" in the user
+// config: a user context plus a context for each listed project root.
+type Profile struct {
+	User     string            `json:"user,omitempty"`
+	Projects map[string]string `json:"projects,omitempty"` // project root → context; roots may use ~ and $VARS
+}
+
+// ProfileProject is a project root of a profile with the context to switch it to.
+type ProfileProject struct {
+	Root    string
+	Context string
+}
+
+// Profile returns the profile named name, or an error if it is not declared.
+func (c *Config) Profile(name string) (Profile, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q is not declared under \"profiles\" in the user config", name)
+	}
+	return p, nil
+}
+
+// ProfileNames returns the declared profile names in sorted order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProjectList returns the expanded project roots of p sorted by root.
+func (p Profile) ProjectList() ([]ProfileProject, error) {
+	var list []ProfileProject
+	for root, name := range p.Projects {
+		abs, err := expandPath(root)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("project %s: context name is empty", root)
+		}
+		list = append(list, ProfileProject{Root: abs, Context: name})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Root < list[j].Root })
+	return list, nil
+}
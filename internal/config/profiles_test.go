@@ -0,0 +1,37 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileProjectList(t *testing.T) {
+	home := writeUserConfig(t, `{
+		"profiles": {
+			"client-a": {"user": "a", "projects": {"~/src/web": "a-web", "/srv/api": "a-api"}}
+		}
+	}`)
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Profile("client-b"); err == nil {
+		t.Error("expected an error for an undeclared profile")
+	}
+	p, err := cfg.Profile("client-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := p.ProjectList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ProfileProject{{"/srv/api", "a-api"}, {filepath.Join(home, "src", "web"), "a-web"}}
+	if len(list) != 2 || list[0] != want[0] || list[1] != want[1] {
+		t.Errorf("ProjectList = %v, want %v", list, want)
+	}
+
+	if _, err := (Profile{Projects: map[string]string{"relative/dir": "x"}}).ProjectList(); err == nil {
+		t.Error("expected an error for a relative project root")
+	}
+}
@@ -6,31 +6,67 @@ import (
 	"path/filepath"
 
 	"github.com/pfldy2850/claudectx/internal/claude"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
 )
 
-// ScopeType represents the scope of a context (user or project).
+// ScopeType represents the scope of a context (user, project, local, managed,
+// or the name of a custom scope declared in the user config).
 type ScopeType string
 
 const (
 	ScopeUser    ScopeType = "user"
 	ScopeProject ScopeType = "project"
+	ScopeLocal   ScopeType = "local"   // personal, uncommitted project files
+	ScopeManaged ScopeType = "managed" // machine-wide policy settings; explicit only
 )
 
 // ExtraFile represents a standalone file outside .claude/ that is part of a scope
 // (e.g. ~/.claude.json for user scope, CLAUDE.md and .mcp.json for project scope).
 type ExtraFile struct {
-	Path string // absolute path to the file
-	Tag  string // source tag: "claudejson", "claudemd", "mcpjson"
+	Path string `json:"path"` // absolute path to the file
+	Tag  string `json:"tag"`  // source tag: "claudejson", "claudemd", "mcpjson", "claudelocalmd", or a custom scope's tag
 }
 
 // Scope defines where Claude config files live and how they are stored.
 type Scope struct {
 	Type            ScopeType
+	Root            string      // project root of project and local scopes
 	DotClaudeDir    string      // source dir to walk
 	ExtraFiles      []ExtraFile // standalone files outside .claude/
 	StorageDir      string      // where .claudectx data lives
+	StateDir        string      // per-checkout state (active marker, backups); empty means StorageDir
 	IncludePatterns []string
 	ExcludePatterns []string
+
+	// ReservedPatterns match .claude/ files owned by another scope. They are
+	// never managed here, whatever include and exclude patterns are configured.
+	ReservedPatterns []string
+
+	// Config is the config file to load; empty means <StorageDir>/config.json.
+	Config string
+
+	// Sources records what determined each path, keyed by "dotClaudeDir",
+	// "storageDir", "root" or an extra file's tag. Shown by show and doctor.
+	Sources map[string]string
+}
+
+// ConfigFile returns the scope's config file.
+func (s *Scope) ConfigFile() string {
+	if s.Config != "" {
+		return s.Config
+	}
+	return filepath.Join(s.StorageDir, "config.json")
+}
+
+// Reserved reports whether a path relative to DotClaudeDir belongs to another
+// scope and must not be managed by this one.
+func (s *Scope) Reserved(rel string) bool {
+	return fileutil.MatchesAny(rel, s.ReservedPatterns)
+}
+
+// InProject reports whether the scope's files live in a project directory.
+func (s *Scope) InProject() bool {
+	return s.Type == ScopeProject || s.Type == ScopeLocal
 }
 
 // ExtraFileByTag returns the ExtraFile matching the given tag, or nil if not found.
@@ -45,43 +81,106 @@ func (s *Scope) ExtraFileByTag(tag string) *ExtraFile {
 
 // UserScope builds a Scope for user-level config (~/.claude/ + ~/.claude.json).
 func UserScope() (*Scope, error) {
-	dotClaudeDir, err := claude.DotClaudeDir()
+	dotClaudeDir, err := claude.ResolveDotClaudeDir()
 	if err != nil {
 		return nil, err
 	}
-	claudeJSONPath, err := claude.ClaudeJSONPath()
+	claudeJSON, err := claude.ResolveClaudeJSONPath()
 	if err != nil {
 		return nil, err
 	}
-	storageDir, err := DefaultStorageDir()
+	storageDir, err := ResolveStorageDir()
+	if err != nil {
+		return nil, err
+	}
+	configFile, err := UserConfigPath()
 	if err != nil {
 		return nil, err
 	}
 	return &Scope{
 		Type:         ScopeUser,
-		DotClaudeDir: dotClaudeDir,
+		DotClaudeDir: dotClaudeDir.Path,
 		ExtraFiles: []ExtraFile{
-			{Path: claudeJSONPath, Tag: "claudejson"},
+			{Path: claudeJSON.Path, Tag: "claudejson"},
 		},
-		StorageDir:      storageDir,
+		StorageDir:      storageDir.Path,
 		IncludePatterns: DefaultIncludePatterns,
 		ExcludePatterns: DefaultExcludePatterns,
+		Config:          configFile,
+		Sources: map[string]string{
+			"dotClaudeDir": dotClaudeDir.Source,
+			"claudejson":   claudeJSON.Source,
+			"storageDir":   storageDir.Source,
+		},
 	}, nil
 }
 
 // ProjectScopeAt builds a project Scope rooted at the given directory (no detection).
+// In a linked git worktree with the "shared" worktree mode, contexts are stored
+// in the main worktree while the active marker and backups stay per worktree.
 func ProjectScopeAt(root string) *Scope {
-	return &Scope{
+	scope := &Scope{
 		Type:         ScopeProject,
+		Root:         root,
 		DotClaudeDir: filepath.Join(root, ".claude"),
 		ExtraFiles: []ExtraFile{
 			{Path: filepath.Join(root, "CLAUDE.md"), Tag: "claudemd"},
 			{Path: filepath.Join(root, ".mcp.json"), Tag: "mcpjson"},
 		},
-		StorageDir:      filepath.Join(root, ".claudectx"),
-		IncludePatterns: DefaultProjectIncludePatterns,
-		ExcludePatterns: DefaultProjectExcludePatterns,
+		StorageDir:       filepath.Join(root, ".claudectx"),
+		IncludePatterns:  DefaultProjectIncludePatterns,
+		ExcludePatterns:  DefaultProjectExcludePatterns,
+		ReservedPatterns: DefaultLocalIncludePatterns,
+		Sources: map[string]string{
+			"dotClaudeDir": "project root",
+			"claudemd":     "project root",
+			"mcpjson":      "project root",
+			"storageDir":   "project root",
+		},
 	}
+	if shared := sharedWorktreeStorage(root); shared != "" {
+		scope.StateDir = scope.StorageDir
+		scope.StorageDir = shared
+		scope.Sources["storageDir"] = "worktrees: shared"
+	}
+	return scope
+}
+
+// withRoot records how the project root of scope was found.
+func withRoot(scope *Scope, source string) *Scope {
+	if scope.Sources == nil {
+		scope.Sources = map[string]string{}
+	}
+	scope.Sources["root"] = source
+	return scope
+}
+
+// LocalScopeAt builds a local Scope rooted at the given directory: the
+// personal project files Claude Code keeps out of version control
+// (.claude/settings.local.json and CLAUDE.local.md). Its contexts are stored
+// in <root>/.claudectx/local/, apart from the project scope's.
+func LocalScopeAt(root string) *Scope {
+	project := ProjectScopeAt(root)
+	scope := &Scope{
+		Type:         ScopeLocal,
+		Root:         root,
+		DotClaudeDir: project.DotClaudeDir,
+		ExtraFiles: []ExtraFile{
+			{Path: filepath.Join(root, "CLAUDE.local.md"), Tag: "claudelocalmd"},
+		},
+		StorageDir:      filepath.Join(project.StorageDir, "local"),
+		IncludePatterns: DefaultLocalIncludePatterns,
+		ExcludePatterns: DefaultLocalExcludePatterns,
+		Sources: map[string]string{
+			"dotClaudeDir":  "project root",
+			"claudelocalmd": "project root",
+			"storageDir":    project.Sources["storageDir"],
+		},
+	}
+	if project.StateDir != "" {
+		scope.StateDir = filepath.Join(project.StateDir, "local")
+	}
+	return scope
 }
 
 // ProjectScope builds a Scope for project-level config (<git-root>/.claude/ + <git-root>/CLAUDE.md).
@@ -96,24 +195,37 @@ func ProjectScope() (*Scope, error) {
 // DetectProjectRoot returns the project root using the composite detection chain:
 // Claude marker files first, then git root. Returns an error if neither is found.
 func DetectProjectRoot() (string, error) {
+	root, _, err := detectProjectRoot()
+	return root, err
+}
+
+// detectProjectRoot is DetectProjectRoot that also reports which step of the
+// chain found the root.
+func detectProjectRoot() (string, string, error) {
 	if root, err := claude.FindMarkerRoot(); err == nil {
-		return root, nil
+		return root, "marker files", nil
 	}
-	return claude.ProjectRoot()
+	root, err := claude.ProjectRoot()
+	return root, "git repository", err
 }
 
 // ResolveScopeWithRoot returns a Scope using the composite detection chain.
 // rootOverride takes highest priority, then scopeOverride, then auto-detection.
 func ResolveScopeWithRoot(scopeOverride, rootOverride string) (*Scope, error) {
 	// Validate scopeOverride early
-	if scopeOverride != "" && scopeOverride != "user" && scopeOverride != "project" {
-		return nil, fmt.Errorf("invalid scope %q: must be 'user' or 'project'", scopeOverride)
+	switch scopeOverride {
+	case "", "user", "project", "local", "managed":
+	default:
+		if rootOverride != "" {
+			return nil, fmt.Errorf("%w: --root cannot be used with --scope %s", ErrInvalidScope, scopeOverride)
+		}
+		return CustomScope(scopeOverride)
 	}
 
 	// --root flag: explicit project root
 	if rootOverride != "" {
-		if scopeOverride == "user" {
-			return nil, fmt.Errorf("--root cannot be used with --scope user")
+		if scopeOverride == "user" || scopeOverride == "managed" {
+			return nil, fmt.Errorf("%w: --root cannot be used with --scope %s", ErrInvalidScope, scopeOverride)
 		}
 		absRoot, err := filepath.Abs(rootOverride)
 		if err != nil {
@@ -121,31 +233,46 @@ func ResolveScopeWithRoot(scopeOverride, rootOverride string) (*Scope, error) {
 		}
 		info, err := os.Stat(absRoot)
 		if err != nil {
-			return nil, fmt.Errorf("root directory %q does not exist", rootOverride)
+			return nil, fmt.Errorf("%w: root directory %q does not exist", ErrInvalidScope, rootOverride)
 		}
 		if !info.IsDir() {
-			return nil, fmt.Errorf("root path %q is not a directory", rootOverride)
+			return nil, fmt.Errorf("%w: root path %q is not a directory", ErrInvalidScope, rootOverride)
+		}
+		if scopeOverride == "local" {
+			return withRoot(LocalScopeAt(absRoot), "--root"), nil
 		}
-		return ProjectScopeAt(absRoot), nil
+		return withRoot(ProjectScopeAt(absRoot), "--root"), nil
 	}
 
 	switch scopeOverride {
 	case "user":
 		return UserScope()
+	case "managed":
+		return ManagedScope(), nil
 	case "project":
 		// Explicit project: composite detection with CWD fallback
-		if root, err := DetectProjectRoot(); err == nil {
-			return ProjectScopeAt(root), nil
+		if root, source, err := detectProjectRoot(); err == nil {
+			return withRoot(ProjectScopeAt(root), source), nil
 		}
 		// Fallback to CWD
 		cwd, err := os.Getwd()
 		if err != nil {
 			return nil, fmt.Errorf("get working directory: %w", err)
 		}
-		return ProjectScopeAt(cwd), nil
+		return withRoot(ProjectScopeAt(cwd), "current directory"), nil
+	case "local":
+		// Local files belong to a project: same detection as "project"
+		root, source, err := detectProjectRoot()
+		if err != nil {
+			if root, err = os.Getwd(); err != nil {
+				return nil, fmt.Errorf("get working directory: %w", err)
+			}
+			source = "current directory"
+		}
+		return withRoot(LocalScopeAt(root), source), nil
 	default: // "" — auto-detect: composite detection, user scope fallback
-		if root, err := DetectProjectRoot(); err == nil {
-			return ProjectScopeAt(root), nil
+		if root, source, err := detectProjectRoot(); err == nil {
+			return withRoot(ProjectScopeAt(root), source), nil
 		}
 		return UserScope()
 	}
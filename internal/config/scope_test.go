@@ -109,6 +109,27 @@ func TestProjectScopeAt(t *testing.T) {
 	}
 }
 
+func TestLocalScopeAt(t *testing.T) {
+	root := "/tmp/my-project"
+	scope := LocalScopeAt(root)
+
+	if scope.Type != ScopeLocal {
+		t.Errorf("expected local scope, got %s", scope.Type)
+	}
+	if !scope.InProject() {
+		t.Error("expected local scope to be in a project")
+	}
+	if scope.DotClaudeDir != filepath.Join(root, ".claude") {
+		t.Errorf("expected DotClaudeDir %s, got %s", filepath.Join(root, ".claude"), scope.DotClaudeDir)
+	}
+	if scope.StorageDir != filepath.Join(root, ".claudectx", "local") {
+		t.Errorf("expected StorageDir %s, got %s", filepath.Join(root, ".claudectx", "local"), scope.StorageDir)
+	}
+	if len(scope.ExtraFiles) != 1 || scope.ExtraFileByTag("claudelocalmd") == nil {
+		t.Errorf("expected only the claudelocalmd extra file, got %+v", scope.ExtraFiles)
+	}
+}
+
 func TestDetectProjectRoot(t *testing.T) {
 	t.Run("marker only (no git)", func(t *testing.T) {
 		tmp := t.TempDir()
@@ -297,6 +318,29 @@ func TestResolveScope(t *testing.T) {
 		}
 	})
 
+	t.Run("explicit local", func(t *testing.T) {
+		tmp := t.TempDir()
+		os.Mkdir(filepath.Join(tmp, ".git"), 0755)
+		sub := filepath.Join(tmp, "sub")
+		os.Mkdir(sub, 0755)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(sub)
+
+		scope, err := ResolveScope("local")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scope.Type != ScopeLocal {
+			t.Errorf("expected local, got %s", scope.Type)
+		}
+		root, _ := filepath.EvalSymlinks(tmp)
+		if scope.DotClaudeDir != filepath.Join(root, ".claude") {
+			t.Errorf("expected DotClaudeDir at project root, got %s", scope.DotClaudeDir)
+		}
+	})
+
 	t.Run("invalid scope", func(t *testing.T) {
 		_, err := ResolveScope("invalid")
 		if err == nil {
@@ -0,0 +1,23 @@
+package config
+
+// What Save and Restore do when a managed JSON file fails schema validation.
+const (
+	ValidateWarn  = "warn"  // print the problems and continue
+	ValidateError = "error" // refuse the operation
+	ValidateOff   = "off"   // skip validation
+)
+
+// ValidationPolicy controls schema validation of managed JSON files
+// (settings.json, settings.local.json, .mcp.json, .claude.json).
+type ValidationPolicy struct {
+	Mode string `json:"mode,omitempty"` // one of the Validate* modes; default "warn"
+}
+
+// Action returns the configured validation mode.
+func (p ValidationPolicy) Action() string {
+	switch p.Mode {
+	case ValidateError, ValidateOff:
+		return p.Mode
+	}
+	return ValidateWarn
+}
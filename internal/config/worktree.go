@@ -0,0 +1,55 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/claude"
+)
+
+// Modes for project scope inside linked git worktrees, set with "worktrees"
+// in the user config (~/.claudectx/config.json).
+const (
+	WorktreesSeparate = "separate" // each worktree has its own .claudectx/ (default)
+	WorktreesShared   = "shared"   // worktrees use the main worktree's contexts
+)
+
+// WorktreeMode returns the configured worktree mode.
+func (c *Config) WorktreeMode() string {
+	if c.Worktrees == WorktreesShared {
+		return WorktreesShared
+	}
+	return WorktreesSeparate
+}
+
+// sharedWorktreeStorage returns the main worktree's .claudectx/ when root is
+// a linked worktree and the user config selects the shared mode, or "".
+func sharedWorktreeStorage(root string) string {
+	repo, err := claude.OpenGitRepo(root)
+	if err != nil || !repo.LinkedWorktree() || repo.MainRoot() == "" {
+		return ""
+	}
+	if userWorktreeMode() != WorktreesShared {
+		return ""
+	}
+	return filepath.Join(repo.MainRoot(), ".claudectx")
+}
+
+// userWorktreeMode reads the worktree mode from the user config. The project
+// config cannot hold it, since it lives in the storage the mode selects.
+func userWorktreeMode() string {
+	path, err := UserConfigPath()
+	if err != nil {
+		return WorktreesSeparate
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return WorktreesSeparate
+	}
+	var cfg Config
+	if json.Unmarshal(data, &cfg) != nil {
+		return WorktreesSeparate
+	}
+	return cfg.WorktreeMode()
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupWorktree creates a main checkout and a linked worktree, and points
+// HOME at a user config selecting mode ("" for no config).
+func setupWorktree(t *testing.T, mode string) (main, wt string) {
+	t.Helper()
+	tmp := t.TempDir()
+	home := filepath.Join(tmp, "home")
+	t.Setenv("HOME", home)
+	if mode != "" {
+		os.MkdirAll(filepath.Join(home, ".claudectx"), 0755)
+		os.WriteFile(filepath.Join(home, ".claudectx", "config.json"), []byte(`{"worktrees": "`+mode+`"}`), 0644)
+	}
+
+	main = filepath.Join(tmp, "main")
+	wt = filepath.Join(tmp, "wt")
+	wtGitDir := filepath.Join(main, ".git", "worktrees", "wt")
+	os.MkdirAll(wtGitDir, 0755)
+	os.MkdirAll(wt, 0755)
+	os.WriteFile(filepath.Join(wtGitDir, "commondir"), []byte("../..\n"), 0644)
+	os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+wtGitDir+"\n"), 0644)
+	return main, wt
+}
+
+func TestProjectScopeAt_WorktreeSeparate(t *testing.T) {
+	for _, mode := range []string{"", WorktreesSeparate} {
+		_, wt := setupWorktree(t, mode)
+		scope := ProjectScopeAt(wt)
+
+		if scope.StorageDir != filepath.Join(wt, ".claudectx") {
+			t.Errorf("mode %q: StorageDir = %s, want worktree's .claudectx", mode, scope.StorageDir)
+		}
+		if scope.StateDir != "" {
+			t.Errorf("mode %q: StateDir = %s, want empty", mode, scope.StateDir)
+		}
+	}
+}
+
+func TestProjectScopeAt_WorktreeShared(t *testing.T) {
+	main, wt := setupWorktree(t, WorktreesShared)
+	scope := ProjectScopeAt(wt)
+
+	if scope.StorageDir != filepath.Join(main, ".claudectx") {
+		t.Errorf("StorageDir = %s, want main worktree's .claudectx", scope.StorageDir)
+	}
+	if scope.DotClaudeDir != filepath.Join(wt, ".claude") {
+		t.Errorf("DotClaudeDir = %s, want worktree's .claude", scope.DotClaudeDir)
+	}
+
+	cfg, err := LoadWithScope("", scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ContextsDir() != filepath.Join(main, ".claudectx", "contexts") {
+		t.Errorf("ContextsDir = %s, want shared", cfg.ContextsDir())
+	}
+	if cfg.CurrentFile() != filepath.Join(wt, ".claudectx", "current") {
+		t.Errorf("CurrentFile = %s, want per worktree", cfg.CurrentFile())
+	}
+	if cfg.BackupsDir() != filepath.Join(wt, ".claudectx", "backups") {
+		t.Errorf("BackupsDir = %s, want per worktree", cfg.BackupsDir())
+	}
+
+	// The main worktree itself is unaffected.
+	if got := ProjectScopeAt(main); got.StorageDir != filepath.Join(main, ".claudectx") || got.StateDir != "" {
+		t.Errorf("main worktree scope = %+v", got)
+	}
+}
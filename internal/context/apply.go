@@ -0,0 +1,100 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
+)
+
+// ApplyOptions configures a partial restore of selected files from a context.
+type ApplyOptions struct {
+	Name    string
+	Only    []string // source tags (e.g. "mcpjson") or globs matched against FileEntry.RelPath
+	DryRun  bool
+	Force   bool
+	Verbose bool
+	Config  *config.Config
+}
+
+// ApplyResult holds the result of an apply operation.
+type ApplyResult struct {
+	Name         string   `json:"name"`
+	FilesApplied int      `json:"filesApplied"`
+	Files        []string `json:"files"` // RelPaths of the applied entries
+	BackupDir    string   `json:"backupDir,omitempty"`
+}
+
+// Apply copies a subset of a saved context's files into the live scope.
+// Unlike Restore, other managed files are left untouched and the current
+// marker is not changed.
+func Apply(opts ApplyOptions) (*ApplyResult, error) {
+	slug := Slugify(opts.Name)
+	cfg := opts.Config
+	scope := cfg.Scope
+
+	if len(opts.Only) == 0 {
+		return nil, fmt.Errorf("no files selected: at least one source or pattern is required")
+	}
+
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	manifest, err := ReadManifest(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
+	}
+
+	if manifest.Scope != "" && manifest.Scope != string(scope.Type) {
+		return nil, fmt.Errorf("%w: context %q was saved with %s scope, but current scope is %s", ErrScopeMismatch, slug, manifest.Scope, scope.Type)
+	}
+
+	selected := SelectEntries(manifest.Files, opts.Only)
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no files in context %q match %v", slug, opts.Only)
+	}
+
+	result := &ApplyResult{Name: slug}
+	for _, entry := range selected {
+		result.Files = append(result.Files, entry.RelPath)
+	}
+
+	if opts.DryRun {
+		result.FilesApplied = len(selected)
+		return result, nil
+	}
+
+	backupDir, err := createBackup(cfg, scope)
+	if err != nil && !opts.Force {
+		return nil, fmt.Errorf("backup failed: %w (use --force to skip)", err)
+	}
+	result.BackupDir = backupDir
+
+	for _, entry := range selected {
+		dstPath, ok := livePath(scope, entry)
+		if !ok {
+			continue
+		}
+		srcPath := filepath.Join(contextDir, filepath.FromSlash(entry.RelPath))
+		if err := fileutil.CopyFile(srcPath, dstPath); err != nil {
+			return nil, fmt.Errorf("apply %s: %w", entry.RelPath, err)
+		}
+		result.FilesApplied++
+	}
+
+	return result, nil
+}
+
+// SelectEntries returns the entries whose source tag equals one of the
+// selectors or whose RelPath matches one of them as a glob pattern.
+func SelectEntries(files []FileEntry, selectors []string) []FileEntry {
+	var selected []FileEntry
+	for _, f := range files {
+		for _, sel := range selectors {
+			if f.Source == sel || fileutil.MatchesAny(f.RelPath, []string{sel}) {
+				selected = append(selected, f)
+				break
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,153 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// newProjectTestConfig builds an isolated project-scope config with
+// CLAUDE.md and .mcp.json as extra files.
+func newProjectTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	projectRoot := t.TempDir()
+	storageDir := filepath.Join(projectRoot, ".claudectx")
+	dotClaudeDir := filepath.Join(projectRoot, ".claude")
+	os.MkdirAll(dotClaudeDir, 0755)
+
+	return &config.Config{
+		StorageDir:      storageDir,
+		IncludePatterns: config.DefaultProjectIncludePatterns,
+		ExcludePatterns: config.DefaultProjectExcludePatterns,
+		Scope: &config.Scope{
+			Type:         config.ScopeProject,
+			DotClaudeDir: dotClaudeDir,
+			ExtraFiles: []config.ExtraFile{
+				{Path: filepath.Join(projectRoot, "CLAUDE.md"), Tag: "claudemd"},
+				{Path: filepath.Join(projectRoot, ".mcp.json"), Tag: "mcpjson"},
+			},
+			StorageDir:      storageDir,
+			IncludePatterns: config.DefaultProjectIncludePatterns,
+			ExcludePatterns: config.DefaultProjectExcludePatterns,
+		},
+	}
+}
+
+func TestApplyOnlySource(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	dotClaudeDir := cfg.Scope.DotClaudeDir
+	claudeMDPath := cfg.Scope.ExtraFileByTag("claudemd").Path
+	mcpJSONPath := cfg.Scope.ExtraFileByTag("mcpjson").Path
+
+	// Context A has its own MCP servers
+	os.WriteFile(filepath.Join(dotClaudeDir, "settings.json"), []byte(`{"ctx":"a"}`), 0644)
+	os.WriteFile(claudeMDPath, []byte("# Context A"), 0644)
+	os.WriteFile(mcpJSONPath, []byte(`{"mcpServers":{"a":{}}}`), 0644)
+	if _, err := Save(SaveOptions{Name: "ctx-a", Config: cfg}); err != nil {
+		t.Fatalf("Save ctx-a failed: %v", err)
+	}
+
+	// Context B is active with different files
+	os.WriteFile(filepath.Join(dotClaudeDir, "settings.json"), []byte(`{"ctx":"b"}`), 0644)
+	os.WriteFile(claudeMDPath, []byte("# Context B"), 0644)
+	os.WriteFile(mcpJSONPath, []byte(`{"mcpServers":{"b":{}}}`), 0644)
+	if _, err := Save(SaveOptions{Name: "ctx-b", Config: cfg}); err != nil {
+		t.Fatalf("Save ctx-b failed: %v", err)
+	}
+
+	result, err := Apply(ApplyOptions{Name: "ctx-a", Only: []string{"mcpjson"}, Config: cfg})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result.FilesApplied != 1 {
+		t.Errorf("expected 1 file applied, got %d", result.FilesApplied)
+	}
+
+	data, _ := os.ReadFile(mcpJSONPath)
+	if string(data) != `{"mcpServers":{"a":{}}}` {
+		t.Errorf("expected ctx-a .mcp.json, got %q", data)
+	}
+
+	// Everything else stays as it was
+	data, _ = os.ReadFile(claudeMDPath)
+	if string(data) != "# Context B" {
+		t.Errorf("expected CLAUDE.md untouched, got %q", data)
+	}
+	data, _ = os.ReadFile(filepath.Join(dotClaudeDir, "settings.json"))
+	if string(data) != `{"ctx":"b"}` {
+		t.Errorf("expected settings.json untouched, got %q", data)
+	}
+
+	current, _ := GetCurrent(cfg)
+	if current != "ctx-b" {
+		t.Errorf("expected current marker unchanged, got %q", current)
+	}
+}
+
+func TestApplyOnlyGlob(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	dotClaudeDir := cfg.Scope.DotClaudeDir
+
+	os.MkdirAll(filepath.Join(dotClaudeDir, "agents"), 0755)
+	os.WriteFile(filepath.Join(dotClaudeDir, "agents", "reviewer.md"), []byte("reviewer"), 0644)
+	os.WriteFile(filepath.Join(dotClaudeDir, "settings.json"), []byte(`{"ctx":"a"}`), 0644)
+	if _, err := Save(SaveOptions{Name: "ctx-a", Config: cfg}); err != nil {
+		t.Fatalf("Save ctx-a failed: %v", err)
+	}
+
+	os.RemoveAll(filepath.Join(dotClaudeDir, "agents"))
+	os.WriteFile(filepath.Join(dotClaudeDir, "settings.json"), []byte(`{"ctx":"live"}`), 0644)
+
+	result, err := Apply(ApplyOptions{Name: "ctx-a", Only: []string{"dotclaude/agents/**"}, Config: cfg})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0] != "dotclaude/agents/reviewer.md" {
+		t.Errorf("unexpected applied files: %v", result.Files)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dotClaudeDir, "agents", "reviewer.md"))
+	if err != nil || string(data) != "reviewer" {
+		t.Errorf("expected agents/reviewer.md to be applied, got %q (%v)", data, err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dotClaudeDir, "settings.json"))
+	if string(data) != `{"ctx":"live"}` {
+		t.Errorf("expected settings.json untouched, got %q", data)
+	}
+}
+
+func TestApplyNoMatchErrors(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(`{}`), 0644)
+	if _, err := Save(SaveOptions{Name: "ctx-a", Config: cfg}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if _, err := Apply(ApplyOptions{Name: "ctx-a", Only: []string{"mcpjson"}, Config: cfg}); err == nil {
+		t.Fatal("expected error when no files match")
+	}
+}
+
+func TestApplyDryRun(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	mcpJSONPath := cfg.Scope.ExtraFileByTag("mcpjson").Path
+	os.WriteFile(mcpJSONPath, []byte(`{"a":1}`), 0644)
+	if _, err := Save(SaveOptions{Name: "ctx-a", Config: cfg}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	os.WriteFile(mcpJSONPath, []byte(`{"live":1}`), 0644)
+
+	result, err := Apply(ApplyOptions{Name: "ctx-a", Only: []string{"mcpjson"}, DryRun: true, Config: cfg})
+	if err != nil {
+		t.Fatalf("Apply dry-run failed: %v", err)
+	}
+	if result.FilesApplied != 1 {
+		t.Errorf("expected 1 file reported, got %d", result.FilesApplied)
+	}
+	data, _ := os.ReadFile(mcpJSONPath)
+	if string(data) != `{"live":1}` {
+		t.Errorf("expected live file untouched in dry-run, got %q", data)
+	}
+}
@@ -0,0 +1,181 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// Thresholds for flagging live changes as suspicious. Small snapshots are
+// not checked for key or file loss because a few removals are normal there.
+const (
+	minKeysForLossCheck  = 4
+	minFilesForLossCheck = 4
+)
+
+// AutoSaveCurrent saves the current live state back to the active context's
+// snapshot before switching away. targetSlug is excluded to avoid saving over
+// the context we're about to switch to. A warning is printed to stderr if the
+// save fails, but the switch is not aborted.
+//
+// If the live files look broken compared to the snapshot (see
+// SuspiciousChanges), cfg.AutoSave decides whether to prompt, keep the old
+// snapshot as a revision, skip the save, or overwrite anyway.
+func AutoSaveCurrent(cfg *config.Config, targetSlug string) {
+	current, err := GetCurrent(cfg)
+	if err != nil || current == "" || current == targetSlug {
+		return
+	}
+	if !ContextExists(cfg.ContextsDir(), current) {
+		return
+	}
+	if IsLocked(cfg, current) {
+		saveLockedEdits(cfg, current)
+		return
+	}
+
+	keep := false
+	if reasons, err := SuspiciousChanges(cfg, current); err == nil && len(reasons) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: live files differ suspiciously from context %q:\n", current)
+		for _, r := range reasons {
+			fmt.Fprintf(os.Stderr, "  - %s\n", r)
+		}
+
+		switch cfg.AutoSave.Action() {
+		case config.AutoSaveSkip:
+			fmt.Fprintf(os.Stderr, "Warning: auto-save of context %q skipped\n", current)
+			return
+		case config.AutoSavePrompt:
+			if cfg.Confirm == nil {
+				keep = true // non-interactive: fall back to keeping a revision
+				break
+			}
+			if !cfg.Confirm(fmt.Sprintf("Overwrite saved context %q with the live files?", current)) {
+				fmt.Fprintf(os.Stderr, "Auto-save of context %q skipped\n", current)
+				return
+			}
+			keep = true
+		case config.AutoSaveRevision:
+			keep = true
+		}
+	}
+
+	// Save live state back to current context (overwrite)
+	if _, err := Save(SaveOptions{
+		Name:         current,
+		Overwrite:    true,
+		KeepRevision: keep,
+		Config:       cfg,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: auto-save of context %q failed: %v\n", current, err)
+		return
+	}
+	if keep {
+		fmt.Fprintf(os.Stderr, "Previous snapshot of %q kept as a revision (see 'claudectx revisions %s')\n", current, current)
+	}
+}
+
+// saveLockedEdits handles switching away from a locked context: the snapshot
+// is never overwritten, but if the live files were edited the user is warned
+// and offered to keep the edits in a new context.
+func saveLockedEdits(cfg *config.Config, current string) {
+	drift, err := Drift(cfg, current)
+	if err != nil || drift.Clean() {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: context %q is locked; live edits to %d files will not be saved to it\n",
+		current, len(drift.Added)+len(drift.Removed)+len(drift.Modified))
+	if cfg.Confirm == nil {
+		return
+	}
+
+	name := fmt.Sprintf("%s-edits-%s", current, time.Now().Format("20060102-150405"))
+	if !cfg.Confirm(fmt.Sprintf("Save the edits as new context %q?", name)) {
+		return
+	}
+	if _, err := Save(SaveOptions{
+		Name:        name,
+		Description: fmt.Sprintf("Edits made on locked context %q", current),
+		Config:      cfg,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: saving edits to %q failed: %v\n", name, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Edits saved as context %q\n", name)
+}
+
+// SuspiciousChanges compares the live managed files against a saved context
+// and returns reasons the live state looks accidentally broken: a file that
+// became empty, a JSON file that no longer parses or lost most of its keys,
+// or a sharp drop in the number of managed files.
+func SuspiciousChanges(cfg *config.Config, slug string) ([]string, error) {
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	manifest, err := ReadManifest(contextDir)
+	if err != nil {
+		return nil, err
+	}
+	live, err := collectLiveFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	liveByPath := make(map[string]liveFile, len(live))
+	for _, lf := range live {
+		liveByPath[lf.RelPath] = lf
+	}
+
+	var reasons []string
+	for _, entry := range manifest.Files {
+		lf, ok := liveByPath[entry.RelPath]
+		if !ok || entry.Size == 0 {
+			continue
+		}
+		if lf.Info.Size() == 0 {
+			reasons = append(reasons, fmt.Sprintf("%s became empty", entry.RelPath))
+			continue
+		}
+		if !strings.HasSuffix(entry.RelPath, ".json") {
+			continue
+		}
+		saved, err := os.ReadFile(filepath.Join(contextDir, filepath.FromSlash(entry.RelPath)))
+		if err != nil || !json.Valid(saved) {
+			continue
+		}
+		current, err := os.ReadFile(lf.AbsPath)
+		if err != nil {
+			continue
+		}
+		if !json.Valid(current) {
+			reasons = append(reasons, fmt.Sprintf("%s is no longer valid JSON", entry.RelPath))
+			continue
+		}
+		if lost, total := lostTopLevelKeys(saved, current); total >= minKeysForLossCheck && lost*2 > total {
+			reasons = append(reasons, fmt.Sprintf("%s lost %d of %d top-level keys", entry.RelPath, lost, total))
+		}
+	}
+
+	if n := len(manifest.Files); n >= minFilesForLossCheck && len(live)*2 < n {
+		reasons = append(reasons, fmt.Sprintf("managed file count dropped from %d to %d", n, len(live)))
+	}
+	return reasons, nil
+}
+
+// lostTopLevelKeys returns how many of the saved object's top-level keys are
+// missing from the current object, and the saved key count.
+func lostTopLevelKeys(saved, current []byte) (lost, total int) {
+	var before, after map[string]json.RawMessage
+	if json.Unmarshal(saved, &before) != nil || json.Unmarshal(current, &after) != nil {
+		return 0, 0
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			lost++
+		}
+	}
+	return lost, len(before)
+}
@@ -0,0 +1,174 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+const goodSettings = `{"model":"opus","theme":"dark","permissions":{},"env":{},"hooks":{}}`
+
+func TestSuspiciousChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(cfg *config.Config)
+		wantSub string
+	}{
+		{"empty file", func(cfg *config.Config) {
+			os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), nil, 0644)
+		}, "became empty"},
+		{"invalid JSON", func(cfg *config.Config) {
+			os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(`{"model":`), 0644)
+		}, "no longer valid JSON"},
+		{"lost keys", func(cfg *config.Config) {
+			os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(`{"model":"opus"}`), 0644)
+		}, "lost 4 of 5 top-level keys"},
+		{"file count drop", func(cfg *config.Config) {
+			os.RemoveAll(filepath.Join(cfg.Scope.DotClaudeDir, "rules"))
+		}, "file count dropped from 5 to 2"},
+		{"normal edit", func(cfg *config.Config) {
+			os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(`{"model":"sonnet","theme":"dark","permissions":{},"env":{}}`), 0644)
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newProjectTestConfig(t)
+			saveProjectContext(t, cfg, "ctx", map[string]string{
+				"CLAUDE.md":     "# Project",
+				"settings.json": goodSettings,
+				"rules/a.md":    "a",
+				"rules/b.md":    "b",
+				"rules/c.md":    "c",
+			})
+			tt.mutate(cfg)
+
+			reasons, err := SuspiciousChanges(cfg, "ctx")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantSub == "" {
+				if len(reasons) != 0 {
+					t.Errorf("expected no reasons, got %v", reasons)
+				}
+				return
+			}
+			if len(reasons) != 1 || !strings.Contains(reasons[0], tt.wantSub) {
+				t.Errorf("expected reason containing %q, got %v", tt.wantSub, reasons)
+			}
+		})
+	}
+}
+
+// setupClobbered saves a good context, makes it current, and breaks the live
+// settings.json so auto-save sees a suspicious change.
+func setupClobbered(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "target", map[string]string{"CLAUDE.md": "# Target"})
+	saveProjectContext(t, cfg, "good", map[string]string{"settings.json": goodSettings})
+	os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(`{}`), 0644)
+	return cfg
+}
+
+func savedSettings(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+	data, _ := os.ReadFile(filepath.Join(cfg.ContextsDir(), "good", "dotclaude", "settings.json"))
+	return string(data)
+}
+
+func TestAutoSaveRevisionPolicy(t *testing.T) {
+	cfg := setupClobbered(t)
+
+	AutoSaveCurrent(cfg, "target")
+
+	if got := savedSettings(t, cfg); got != `{}` {
+		t.Errorf("expected snapshot to be overwritten, got %q", got)
+	}
+	revs, err := ListRevisions(cfg, "good")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 1 {
+		t.Fatalf("expected 1 revision, got %d", len(revs))
+	}
+	data, _ := os.ReadFile(filepath.Join(revs[0].Dir, "dotclaude", "settings.json"))
+	if string(data) != goodSettings {
+		t.Errorf("expected revision to hold the good snapshot, got %q", data)
+	}
+
+	if err := RestoreRevision(cfg, "good", revs[0].ID); err != nil {
+		t.Fatalf("RestoreRevision failed: %v", err)
+	}
+	if got := savedSettings(t, cfg); got != goodSettings {
+		t.Errorf("expected restored revision, got %q", got)
+	}
+}
+
+func TestAutoSaveSkipPolicy(t *testing.T) {
+	cfg := setupClobbered(t)
+	cfg.AutoSave.OnSuspicious = config.AutoSaveSkip
+
+	AutoSaveCurrent(cfg, "target")
+
+	if got := savedSettings(t, cfg); got != goodSettings {
+		t.Errorf("expected snapshot untouched, got %q", got)
+	}
+}
+
+func TestAutoSavePromptDeclined(t *testing.T) {
+	cfg := setupClobbered(t)
+	cfg.AutoSave.OnSuspicious = config.AutoSavePrompt
+	asked := false
+	cfg.Confirm = func(string) bool {
+		asked = true
+		return false
+	}
+
+	AutoSaveCurrent(cfg, "target")
+
+	if !asked {
+		t.Error("expected a confirmation prompt")
+	}
+	if got := savedSettings(t, cfg); got != goodSettings {
+		t.Errorf("expected snapshot untouched, got %q", got)
+	}
+}
+
+func TestAutoSaveOverwritesNormalEdits(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "target", map[string]string{"CLAUDE.md": "# Target"})
+	saveProjectContext(t, cfg, "good", map[string]string{"settings.json": goodSettings})
+	os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(`{"model":"sonnet","theme":"dark","permissions":{},"env":{},"hooks":{}}`), 0644)
+
+	AutoSaveCurrent(cfg, "target")
+
+	if revs, _ := ListRevisions(cfg, "good"); len(revs) != 0 {
+		t.Errorf("expected no revision for a normal edit, got %d", len(revs))
+	}
+}
+
+func TestRevisionsArePruned(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	cfg.AutoSave.MaxRevisions = 2
+	saveProjectContext(t, cfg, "ctx", map[string]string{"CLAUDE.md": "v0"})
+
+	for _, v := range []string{"v1", "v2", "v3"} {
+		os.WriteFile(cfg.Scope.ExtraFileByTag("claudemd").Path, []byte(v), 0644)
+		if _, err := Save(SaveOptions{Name: "ctx", Overwrite: true, KeepRevision: true, Config: cfg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	revs, _ := ListRevisions(cfg, "ctx")
+	if len(revs) != 2 {
+		t.Fatalf("expected 2 revisions after pruning, got %d", len(revs))
+	}
+	data, _ := os.ReadFile(filepath.Join(revs[0].Dir, "CLAUDE.md"))
+	if string(data) != "v2" {
+		t.Errorf("expected newest revision to hold v2, got %q", data)
+	}
+}
@@ -0,0 +1,57 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// Backup is a copy of the live managed files taken before a switch or apply.
+type Backup struct {
+	Name      string
+	Dir       string
+	CreatedAt time.Time
+	Files     []string // paths relative to Dir, in stored form ("CLAUDE.md", "dotclaude/...")
+	TotalSize int64
+}
+
+// ListBackups returns the backups of the current scope, newest first.
+func ListBackups(cfg *config.Config) ([]Backup, error) {
+	entries, err := os.ReadDir(cfg.BackupsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		b := Backup{Name: e.Name(), Dir: filepath.Join(cfg.BackupsDir(), e.Name())}
+		stamp := strings.TrimPrefix(e.Name(), "pre-switch-")
+		if t, err := time.ParseInLocation("20060102-150405", stamp, time.Local); err == nil {
+			b.CreatedAt = t
+		} else if info, err := e.Info(); err == nil {
+			b.CreatedAt = info.ModTime()
+		}
+		filepath.Walk(b.Dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			rel, _ := filepath.Rel(b.Dir, path)
+			b.Files = append(b.Files, toSlash(rel))
+			b.TotalSize += info.Size()
+			return nil
+		})
+		backups = append(backups, b)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
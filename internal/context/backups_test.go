@@ -0,0 +1,31 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListBackups(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	for _, name := range []string{"pre-switch-20250101-100000", "pre-switch-20250102-100000"} {
+		dir := filepath.Join(cfg.BackupsDir(), name)
+		os.MkdirAll(filepath.Join(dir, "dotclaude"), 0755)
+		os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("# A"), 0644)
+		os.WriteFile(filepath.Join(dir, "dotclaude", "settings.json"), []byte("{}"), 0644)
+	}
+
+	backups, err := ListBackups(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %d", len(backups))
+	}
+	if backups[0].Name != "pre-switch-20250102-100000" {
+		t.Errorf("expected newest first, got %q", backups[0].Name)
+	}
+	if len(backups[0].Files) != 2 || backups[0].Files[1] != "dotclaude/settings.json" || backups[0].TotalSize != 5 {
+		t.Errorf("unexpected backup contents: %+v", backups[0])
+	}
+}
@@ -22,6 +22,12 @@ type Manifest struct {
 	Checksum    string      `json:"checksum"`
 	OAuthEmail  string      `json:"oauthEmail,omitempty"`
 	Scope       string      `json:"scope,omitempty"`
+	Locked      bool        `json:"locked,omitempty"`
+
+	// Free-form metadata set with `claudectx edit`; kept when the snapshot
+	// is overwritten.
+	Tags   []string          `json:"tags,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // FileEntry represents a single file within a context snapshot.
@@ -30,7 +36,7 @@ type FileEntry struct {
 	Size     int64  `json:"size"`
 	Mode     uint32 `json:"mode"`
 	Checksum string `json:"checksum"`
-	Source   string `json:"source"` // "dotclaude", "claudejson", "claudemd", or "mcpjson"
+	Source   string `json:"source"` // "dotclaude" or the tag of one of the scope's extra files
 }
 
 var slugRe = regexp.MustCompile(`[^a-z0-9-]+`)
@@ -58,11 +64,17 @@ func FileChecksum(path string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
+// DataChecksum computes the SHA-256 checksum of in-memory file contents.
+func DataChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // isExtraFileSource returns true if the source tag represents an extra file
-// (claude.json for user scope, CLAUDE.md/.mcp.json for project scope) rather
-// than a file from the .claude/ directory.
+// (claude.json for user scope, CLAUDE.md/.mcp.json for project scope, or any
+// tag of a custom scope) rather than a file from the .claude/ directory.
 func isExtraFileSource(source string) bool {
-	return source == "claudejson" || source == "claudemd" || source == "mcpjson"
+	return source != "dotclaude"
 }
 
 // ManifestChecksum computes a combined checksum from all file entries.
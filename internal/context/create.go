@@ -0,0 +1,133 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// pendingFile is a file ready to be written into a new context.
+type pendingFile struct {
+	entry FileEntry
+	data  []byte
+}
+
+// StoredSource returns the source tag for a path as stored inside a context
+// directory: "dotclaude/..." for files from .claude/, or the base name of one
+// of the scope's extra files (e.g. "CLAUDE.md", ".mcp.json").
+func StoredSource(scope *config.Scope, relPath string) (string, bool) {
+	relPath = filepath.ToSlash(relPath)
+	if strings.HasPrefix(relPath, "dotclaude/") {
+		return "dotclaude", true
+	}
+	for _, ef := range scope.ExtraFiles {
+		if relPath == filepath.Base(ef.Path) {
+			return ef.Tag, true
+		}
+	}
+	return "", false
+}
+
+// CreateFromFiles creates a new context from in-memory files keyed by their
+// stored path (see StoredSource). The current marker is not changed.
+func CreateFromFiles(cfg *config.Config, name, description string, files map[string][]byte) (*SaveResult, error) {
+	slug := Slugify(name)
+	if slug == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	if ContextExists(cfg.ContextsDir(), slug) {
+		return nil, fmt.Errorf("context %q %w", slug, ErrExists)
+	}
+
+	relPaths := make([]string, 0, len(files))
+	for p := range files {
+		relPaths = append(relPaths, p)
+	}
+	sort.Strings(relPaths)
+
+	var outputs []pendingFile
+	var totalSize int64
+	for _, p := range relPaths {
+		relPath := path.Clean(filepath.ToSlash(p))
+		source, ok := StoredSource(cfg.Scope, relPath)
+		if !ok {
+			return nil, fmt.Errorf("file %q is not managed by %s scope", p, cfg.Scope.Type)
+		}
+		outputs = append(outputs, pendingFile{
+			entry: FileEntry{RelPath: relPath, Source: source, Mode: 0644},
+			data:  files[p],
+		})
+		totalSize += int64(len(files[p]))
+	}
+
+	dir, err := writeNewContext(cfg, slug, description, string(cfg.Scope.Type), outputs)
+	if err != nil {
+		return nil, err
+	}
+	return &SaveResult{
+		Name:      slug,
+		Dir:       dir,
+		Files:     len(outputs),
+		TotalSize: totalSize,
+	}, nil
+}
+
+// writeNewContext writes files and a manifest into a temporary directory,
+// then renames it into place so a failed write leaves no partial context.
+func writeNewContext(cfg *config.Config, slug, description, scope string, outputs []pendingFile) (string, error) {
+	if err := os.MkdirAll(cfg.ContextsDir(), 0755); err != nil {
+		return "", fmt.Errorf("create contexts dir: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(cfg.ContextsDir(), ".new-*")
+	if err != nil {
+		return "", fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	os.Chmod(tmpDir, 0755)
+
+	files := []FileEntry{}
+	var totalSize int64
+	for _, out := range outputs {
+		entry, err := writeSnapshotFile(tmpDir, out.entry.RelPath, out.entry.Source, out.data, os.FileMode(out.entry.Mode))
+		if err != nil {
+			return "", err
+		}
+		files = append(files, entry)
+		totalSize += entry.Size
+	}
+
+	var oauthEmail string
+	for _, f := range files {
+		if f.Source == "claudejson" {
+			oauthEmail = extractOAuthEmail(filepath.Join(tmpDir, filepath.FromSlash(f.RelPath)))
+		}
+	}
+
+	now := time.Now()
+	manifest := &Manifest{
+		Name:        slug,
+		Description: description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Files:       files,
+		TotalSize:   totalSize,
+		Checksum:    ManifestChecksum(files),
+		OAuthEmail:  oauthEmail,
+		Scope:       scope,
+	}
+	if err := WriteManifest(tmpDir, manifest); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(cfg.ContextsDir(), slug)
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return "", fmt.Errorf("rename new context: %w", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,142 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// DriftResult lists differences between the live files and a saved context.
+// Paths are stored paths ("CLAUDE.md", "dotclaude/settings.json").
+type DriftResult struct {
+	Name     string   `json:"name"`
+	Added    []string `json:"added,omitempty"`    // live files not in the snapshot
+	Removed  []string `json:"removed,omitempty"`  // snapshot files missing from the live scope
+	Modified []string `json:"modified,omitempty"` // files whose content differs
+}
+
+// Clean reports whether the live files match the snapshot exactly.
+func (d *DriftResult) Clean() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Drift compares the live managed files with a saved context's manifest.
+func Drift(cfg *config.Config, slug string) (*DriftResult, error) {
+	manifest, err := ReadManifest(filepath.Join(cfg.ContextsDir(), slug))
+	if err != nil {
+		return nil, err
+	}
+	live, err := collectLiveFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make(map[string]FileEntry, len(manifest.Files))
+	for _, f := range manifest.Files {
+		saved[f.RelPath] = f
+	}
+
+	result := &DriftResult{Name: slug}
+	seen := map[string]bool{}
+	for _, lf := range live {
+		seen[lf.RelPath] = true
+		entry, ok := saved[lf.RelPath]
+		if !ok {
+			result.Added = append(result.Added, lf.RelPath)
+			continue
+		}
+		if entry.Size != lf.Info.Size() {
+			result.Modified = append(result.Modified, lf.RelPath)
+			continue
+		}
+		if sum, err := FileChecksum(lf.AbsPath); err != nil || sum != entry.Checksum {
+			result.Modified = append(result.Modified, lf.RelPath)
+		}
+	}
+	for _, f := range manifest.Files {
+		if _, managed := livePath(cfg.Scope, f); managed && !seen[f.RelPath] {
+			result.Removed = append(result.Removed, f.RelPath)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Modified)
+	return result, nil
+}
+
+// ContextDiff lists differences between two saved contexts.
+type ContextDiff struct {
+	A        string   `json:"a"`
+	B        string   `json:"b"`
+	OnlyInA  []string `json:"onlyInA,omitempty"`  // stored paths missing from B
+	OnlyInB  []string `json:"onlyInB,omitempty"`  // stored paths missing from A
+	Modified []string `json:"modified,omitempty"` // paths in both whose content differs
+
+	ServersOnlyInA []string `json:"serversOnlyInA,omitempty"` // MCP servers configured only in A
+	ServersOnlyInB []string `json:"serversOnlyInB,omitempty"` // MCP servers configured only in B
+}
+
+// CompareContexts compares the manifests and MCP servers of two saved contexts.
+func CompareContexts(cfg *config.Config, a, b string) (*ContextDiff, error) {
+	ma, err := ReadManifest(filepath.Join(cfg.ContextsDir(), a))
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", a, ErrNotFound, err)
+	}
+	mb, err := ReadManifest(filepath.Join(cfg.ContextsDir(), b))
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", b, ErrNotFound, err)
+	}
+
+	result := &ContextDiff{A: a, B: b}
+	inB := make(map[string]FileEntry, len(mb.Files))
+	for _, f := range mb.Files {
+		inB[f.RelPath] = f
+	}
+	for _, f := range ma.Files {
+		other, ok := inB[f.RelPath]
+		switch {
+		case !ok:
+			result.OnlyInA = append(result.OnlyInA, f.RelPath)
+		case other.Checksum != f.Checksum:
+			result.Modified = append(result.Modified, f.RelPath)
+		}
+		delete(inB, f.RelPath)
+	}
+	for relPath := range inB {
+		result.OnlyInB = append(result.OnlyInB, relPath)
+	}
+
+	serversA, err := MCPServers(cfg, a)
+	if err != nil {
+		return nil, err
+	}
+	serversB, err := MCPServers(cfg, b)
+	if err != nil {
+		return nil, err
+	}
+	result.ServersOnlyInA = difference(serversA, serversB)
+	result.ServersOnlyInB = difference(serversB, serversA)
+
+	sort.Strings(result.OnlyInA)
+	sort.Strings(result.OnlyInB)
+	sort.Strings(result.Modified)
+	return result, nil
+}
+
+// difference returns the elements of a that are not in b, keeping a's order.
+func difference(a, b []string) []string {
+	skip := make(map[string]bool, len(b))
+	for _, s := range b {
+		skip[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !skip[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,240 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
+	"github.com/pfldy2850/claudectx/internal/schema"
+)
+
+// FileEdit is a working copy of a saved context opened for editing. The
+// snapshot itself is only replaced by Commit, by swapping directories, so
+// readers never see half-edited files and an aborted edit leaves it untouched.
+type FileEdit struct {
+	Dir   string   // the working copy
+	Paths []string // files to edit, absolute paths inside Dir
+
+	cfg      *config.Config
+	slug     string
+	manifest *Manifest // as read when the edit began
+	keep     bool      // leave the working copy behind on Close
+}
+
+// EditFilesResult holds the result of committing a FileEdit.
+type EditFilesResult struct {
+	Name     string   `json:"name"`
+	Modified []string `json:"modified"`           // RelPaths of the changed files
+	Revision string   `json:"revision,omitempty"` // revision holding the previous snapshot
+	Active   bool     `json:"active,omitempty"`   // the context is active; the changed live files were updated too
+}
+
+// BeginEditFiles copies a saved context into a working copy. If relPath is
+// set, only that file (a stored path such as "CLAUDE.md" or
+// "dotclaude/settings.json"; the "dotclaude/" prefix may be omitted) is
+// offered for editing. Locked contexts cannot be edited.
+func BeginEditFiles(cfg *config.Config, slug, relPath string) (*FileEdit, error) {
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	manifest, err := ReadManifest(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
+	}
+	if manifest.Locked {
+		return nil, lockedError(slug)
+	}
+
+	var entries []FileEntry
+	for _, entry := range manifest.Files {
+		if relPath == "" || entry.RelPath == relPath || entry.RelPath == "dotclaude/"+relPath {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		if relPath != "" {
+			return nil, fmt.Errorf("file %q %w in context %q", relPath, ErrNotFound, slug)
+		}
+		return nil, fmt.Errorf("context %q has no files to edit", slug)
+	}
+
+	if err := os.MkdirAll(cfg.StorageDir, 0755); err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(cfg.StorageDir, ".edit-"+slug+"-")
+	if err != nil {
+		return nil, err
+	}
+	os.Chmod(dir, 0755) // it becomes the context dir on Commit
+	if err := fileutil.CopyDir(contextDir, dir); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("copy context: %w", err)
+	}
+
+	e := &FileEdit{Dir: dir, cfg: cfg, slug: slug, manifest: manifest}
+	for _, entry := range entries {
+		e.Paths = append(e.Paths, filepath.Join(dir, filepath.FromSlash(entry.RelPath)))
+	}
+	return e, nil
+}
+
+// Close removes the working copy, unless Commit kept it after a conflict.
+func (e *FileEdit) Close() {
+	if !e.keep {
+		os.RemoveAll(e.Dir)
+	}
+}
+
+// Validate checks the edited JSON files. Syntax errors are always returned as
+// an *InvalidFilesError; schema problems follow cfg.Validation like a switch,
+// and force lets them through.
+func (e *FileEdit) Validate(force bool) error {
+	var syntax, issues []schema.Issue
+	for _, entry := range e.manifest.Files {
+		if path.Ext(entry.RelPath) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(e.Dir, filepath.FromSlash(entry.RelPath)))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", entry.RelPath, err)
+		}
+		if !json.Valid(data) {
+			if hasSchema(entry.RelPath) {
+				syntax = append(syntax, validateFile(entry.RelPath, data)...) // reports line and column
+			} else {
+				var v any
+				err := json.Unmarshal(data, &v)
+				syntax = append(syntax, schema.Issue{File: entry.RelPath, Message: "invalid JSON: " + err.Error()})
+			}
+			continue
+		}
+		if hasSchema(entry.RelPath) && e.cfg.Validation.Action() != config.ValidateOff {
+			issues = append(issues, validateFile(entry.RelPath, data)...)
+		}
+	}
+	if len(syntax) > 0 {
+		return &InvalidFilesError{Issues: syntax}
+	}
+	return enforceValidation(e.cfg, issues, force)
+}
+
+// Commit records the edited files in the manifest and swaps the working copy
+// in for the snapshot. The previous snapshot is kept as a revision. If
+// nothing changed, the snapshot is left alone.
+//
+// If the context is active, the changed files are also written to their live
+// paths, so switching away does not auto-save the old live files over the
+// edits. Commit refuses if one of those live files has unsaved changes.
+func (e *FileEdit) Commit() (*EditFilesResult, error) {
+	result := &EditFilesResult{Name: e.slug, Modified: []string{}}
+	current, _ := GetCurrent(e.cfg)
+	result.Active = current == e.slug
+
+	m := *e.manifest
+	m.Files = make([]FileEntry, len(e.manifest.Files))
+	m.TotalSize = 0
+	var changed []FileEntry // pre-edit entries of the modified files
+	for i, entry := range e.manifest.Files {
+		p := filepath.Join(e.Dir, filepath.FromSlash(entry.RelPath))
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.RelPath, err)
+		}
+		checksum, err := FileChecksum(p)
+		if err != nil {
+			return nil, err
+		}
+		if checksum != entry.Checksum {
+			result.Modified = append(result.Modified, entry.RelPath)
+			changed = append(changed, entry)
+			if entry.Source == "claudejson" {
+				m.OAuthEmail = extractOAuthEmail(p)
+			}
+		}
+		entry.Size = info.Size()
+		entry.Checksum = checksum
+		m.Files[i] = entry
+		m.TotalSize += entry.Size
+	}
+	if len(result.Modified) == 0 {
+		return result, nil
+	}
+
+	contextDir := filepath.Join(e.cfg.ContextsDir(), e.slug)
+	latest, err := ReadManifest(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", e.slug, ErrNotFound, err)
+	}
+	if latest.Locked {
+		return nil, lockedError(e.slug)
+	}
+	if latest.Checksum != e.manifest.Checksum || !latest.UpdatedAt.Equal(e.manifest.UpdatedAt) {
+		e.keep = true
+		return nil, fmt.Errorf("context %q was saved again while editing; edits kept in %s", e.slug, e.Dir)
+	}
+	if result.Active {
+		for _, entry := range changed {
+			dst, ok := livePath(e.cfg.Scope, entry)
+			if !ok {
+				continue
+			}
+			if sum, err := FileChecksum(dst); err == nil && sum != entry.Checksum {
+				e.keep = true
+				return nil, fmt.Errorf("context %q is %w and live %s has unsaved changes; save or discard them first; edits kept in %s",
+					e.slug, ErrActive, entry.RelPath, e.Dir)
+			}
+		}
+	}
+	// Metadata may have been edited meanwhile; it is not part of the files.
+	m.Description, m.Tags, m.Labels = latest.Description, latest.Tags, latest.Labels
+	m.Checksum = ManifestChecksum(m.Files)
+	m.UpdatedAt = time.Now()
+	if err := WriteManifest(e.Dir, &m); err != nil {
+		return nil, err
+	}
+
+	// The working copy is complete; only now is the snapshot moved aside.
+	if result.Revision, err = keepRevision(e.cfg, e.slug); err != nil {
+		return nil, err
+	}
+	if err := swapDir(e.Dir, contextDir); err != nil {
+		e.keep = true
+		return nil, fmt.Errorf("replace context %q: %w; edits kept in %s", e.slug, err, e.Dir)
+	}
+	if result.Active {
+		for _, entry := range changed {
+			dst, ok := livePath(e.cfg.Scope, entry)
+			if !ok {
+				continue
+			}
+			src := filepath.Join(contextDir, filepath.FromSlash(entry.RelPath))
+			if err := fileutil.CopyFile(src, dst); err != nil {
+				return nil, fmt.Errorf("update live %s: %w", entry.RelPath, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// swapDir replaces dir with newDir using renames, putting dir back if the
+// second rename fails. newDir must already be complete: dir is missing only
+// between the two renames, so a concurrent list or switch never sees the
+// context disappear for longer than that.
+func swapDir(newDir, dir string) error {
+	old := filepath.Join(filepath.Dir(newDir), ".old-"+strings.TrimPrefix(filepath.Base(newDir), ".edit-"))
+	if err := os.Rename(dir, old); err != nil {
+		return err
+	}
+	if err := os.Rename(newDir, dir); err != nil {
+		if rerr := os.Rename(old, dir); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+	return os.RemoveAll(old)
+}
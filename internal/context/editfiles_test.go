@@ -0,0 +1,134 @@
+package context
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+func editFilesProject(t *testing.T) *config.Config {
+	t.Helper()
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".claude"), 0755)
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Project"), 0644)
+	os.WriteFile(filepath.Join(root, ".claude", "settings.json"), []byte(`{"model": "opus"}`), 0644)
+	cfg, err := config.LoadWithScope("", config.ProjectScopeAt(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Save(SaveOptions{Name: "work", Description: "Work", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestEditFiles(t *testing.T) {
+	cfg := editFilesProject(t)
+	contextDir := filepath.Join(cfg.ContextsDir(), "work")
+	before, _ := ReadManifest(contextDir)
+
+	edit, err := BeginEditFiles(cfg, "work", "settings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer edit.Close()
+	if len(edit.Paths) != 1 || filepath.Base(edit.Paths[0]) != "settings.json" {
+		t.Fatalf("Paths = %v, want only settings.json", edit.Paths)
+	}
+
+	os.WriteFile(edit.Paths[0], []byte(`{"model": "sonnet"`), 0644)
+	var invalid *InvalidFilesError
+	if err := edit.Validate(false); !errors.As(err, &invalid) || len(invalid.Issues) != 1 {
+		t.Fatalf("Validate of broken JSON = %v, want one issue", err)
+	}
+
+	os.WriteFile(edit.Paths[0], []byte(`{"model": "sonnet"}`), 0644)
+	if err := edit.Validate(false); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	result, err := edit.Commit()
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(result.Modified) != 1 || result.Modified[0] != "dotclaude/settings.json" || result.Revision == "" || !result.Active {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	after, err := ReadManifest(contextDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(contextDir, "dotclaude", "settings.json"))
+	if string(data) != `{"model": "sonnet"}` {
+		t.Errorf("snapshot file = %s", data)
+	}
+	// The context is active, so the live file follows the edit.
+	live, _ := os.ReadFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"))
+	if string(live) != `{"model": "sonnet"}` {
+		t.Errorf("live file = %s", live)
+	}
+	if after.Checksum == before.Checksum || after.Checksum != ManifestChecksum(after.Files) || !after.UpdatedAt.After(before.UpdatedAt) {
+		t.Errorf("manifest not updated: before %+v, after %+v", before, after)
+	}
+	assertContextDirMode(t, cfg, "work")
+	if after.Description != "Work" || after.TotalSize != before.TotalSize+2 {
+		t.Errorf("Description = %q, TotalSize = %d (was %d)", after.Description, after.TotalSize, before.TotalSize)
+	}
+	entries, _ := os.ReadDir(cfg.StorageDir)
+	for _, e := range entries {
+		if e.Name()[0] == '.' && e.Name() != ".gitignore" {
+			t.Errorf("working copy left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestEditFilesRefusals(t *testing.T) {
+	cfg := editFilesProject(t)
+
+	if _, err := BeginEditFiles(cfg, "work", "missing.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing file: err = %v, want ErrNotFound", err)
+	}
+
+	// A snapshot saved again while editing is not overwritten.
+	edit, err := BeginEditFiles(cfg, "work", "CLAUDE.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(edit.Paths[0], []byte("# Edited"), 0644)
+	if _, err := Save(SaveOptions{Name: "work", Overwrite: true, Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := edit.Commit(); err == nil {
+		t.Error("expected Commit to fail after a concurrent save")
+	}
+	edit.Close()
+	if _, err := os.Stat(edit.Dir); err != nil {
+		t.Errorf("working copy was not kept after the conflict: %v", err)
+	}
+
+	// Unsaved live edits to a changed file of the active context are not
+	// overwritten.
+	os.WriteFile(cfg.Scope.ExtraFileByTag("claudemd").Path, []byte("# Live edit"), 0644)
+	edit, err = BeginEditFiles(cfg, "work", "CLAUDE.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(edit.Paths[0], []byte("# Edited"), 0644)
+	if _, err := edit.Commit(); !errors.Is(err, ErrActive) {
+		t.Errorf("live file with unsaved changes: err = %v, want ErrActive", err)
+	}
+	edit.Close()
+	if live, _ := os.ReadFile(cfg.Scope.ExtraFileByTag("claudemd").Path); string(live) != "# Live edit" {
+		t.Errorf("live CLAUDE.md overwritten: %q", live)
+	}
+
+	if err := SetLocked(cfg, "work", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BeginEditFiles(cfg, "work", ""); !errors.Is(err, ErrLocked) {
+		t.Errorf("locked context: err = %v, want ErrLocked", err)
+	}
+}
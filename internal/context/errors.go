@@ -0,0 +1,17 @@
+package context
+
+import "errors"
+
+// Sentinel errors wrapped by the operations in this package. Test for them
+// with errors.Is; the message of the wrapping error names the context.
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrExists        = errors.New("already exists")
+	ErrInvalidName   = errors.New("invalid context name")
+	ErrScopeMismatch = errors.New("scope mismatch")
+	ErrLocked        = errors.New("locked")
+	ErrActive        = errors.New("active")
+	ErrConflict      = errors.New("unresolved merge conflicts")
+	ErrInvalidFiles  = errors.New("invalid managed files")
+	ErrUncommitted   = errors.New("uncommitted changes")
+)
@@ -0,0 +1,45 @@
+package context
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "work", map[string]string{"CLAUDE.md": "# Work"})
+
+	if _, err := Restore(RestoreOptions{Name: "missing", Config: cfg}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Restore(missing): expected ErrNotFound, got %v", err)
+	}
+	if _, err := Save(SaveOptions{Name: "work", Config: cfg}); !errors.Is(err, ErrExists) {
+		t.Errorf("Save(existing): expected ErrExists, got %v", err)
+	}
+	if _, err := Save(SaveOptions{Name: "!!!", Config: cfg}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Save(!!!): expected ErrInvalidName, got %v", err)
+	}
+
+	if err := SetLocked(cfg, "work", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteContext(cfg, "work"); !errors.Is(err, ErrLocked) {
+		t.Errorf("DeleteContext(locked): expected ErrLocked, got %v", err)
+	}
+	if err := SetLocked(cfg, "work", false); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(cfg.ContextsDir(), "work")
+	m, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Scope = "user"
+	if err := WriteManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(RestoreOptions{Name: "work", Config: cfg}); !errors.Is(err, ErrScopeMismatch) {
+		t.Errorf("Restore(user context): expected ErrScopeMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
+	"github.com/pfldy2850/claudectx/internal/git"
+)
+
+// UncommittedChangesError is returned by Restore when a switch would rewrite
+// git-tracked files that have uncommitted changes.
+type UncommittedChangesError struct {
+	Files []string // relative to the repository root
+}
+
+func (e *UncommittedChangesError) Error() string {
+	return fmt.Sprintf("%d tracked file(s) with %s: %s", len(e.Files), ErrUncommitted, strings.Join(e.Files, ", "))
+}
+
+// Is makes errors.Is(err, ErrUncommitted) match.
+func (e *UncommittedChangesError) Is(target error) bool { return target == ErrUncommitted }
+
+// gitGuard applies cfg.Git to a restore in a project-side scope inside a git
+// working tree. A nil *gitGuard guards nothing.
+type gitGuard struct {
+	repo  *git.Repo
+	mode  string          // one of the config.GitTracked* modes
+	paths []string        // live files the restore removes or writes
+	dests map[string]bool // the subset the snapshot writes
+}
+
+// newGitGuard returns nil outside git or for the user scope.
+func newGitGuard(cfg *config.Config, tracked string, manifest *Manifest) (*gitGuard, error) {
+	scope := cfg.Scope
+	if !scope.InProject() {
+		return nil, nil
+	}
+	repo, err := git.Open(filepath.Dir(scope.DotClaudeDir))
+	if err != nil {
+		return nil, nil // not a git repository (or an unreadable index)
+	}
+	g := &gitGuard{repo: repo, mode: cfg.Git.TrackedMode(), dests: map[string]bool{}}
+	if tracked != "" {
+		g.mode = tracked
+	}
+
+	seen := map[string]bool{}
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			g.paths = append(g.paths, path)
+		}
+	}
+	for _, ef := range scope.ExtraFiles {
+		if _, err := os.Stat(ef.Path); err == nil {
+			add(ef.Path)
+		}
+	}
+	if _, err := os.Stat(scope.DotClaudeDir); err == nil {
+		walked, err := fileutil.WalkFiltered(scope.DotClaudeDir, scope.IncludePatterns, scope.ExcludePatterns)
+		if err != nil {
+			return nil, fmt.Errorf("walk %s: %w", scope.DotClaudeDir, err)
+		}
+		for _, w := range walked {
+			add(w.AbsPath)
+		}
+	}
+	for _, entry := range manifest.Files {
+		if dst, ok := livePath(scope, entry); ok {
+			add(dst)
+			g.dests[dst] = true
+		}
+	}
+	return g, nil
+}
+
+// keep reports whether the restore must leave the live file at path alone.
+func (g *gitGuard) keep(path string) bool {
+	return g != nil && g.mode == config.GitTrackedSkip && g.repo.Tracked(path)
+}
+
+// relPaths returns the guarded paths matching pred, relative to the repository root.
+func (g *gitGuard) relPaths(pred func(string) bool) []string {
+	if g == nil {
+		return nil
+	}
+	var out []string
+	for _, p := range g.paths {
+		if pred(p) {
+			rel, _ := filepath.Rel(g.repo.Root, p)
+			out = append(out, filepath.ToSlash(rel))
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// skipped lists the tracked files the restore leaves alone.
+func (g *gitGuard) skipped() []string {
+	return g.relPaths(g.keep)
+}
+
+// keptRestores counts the snapshot files that will not be written.
+func (g *gitGuard) keptRestores() int {
+	if g == nil {
+		return 0
+	}
+	n := 0
+	for dst := range g.dests {
+		if g.keep(dst) {
+			n++
+		}
+	}
+	return n
+}
+
+// checkModified finds tracked files with uncommitted changes that the
+// restore would rewrite and applies cfg.Git.OnModified to them.
+func (g *gitGuard) checkModified(cfg *config.Config, slug string, force bool) ([]string, error) {
+	action := cfg.Git.ModifiedAction()
+	if action == config.GitModifiedIgnore {
+		return nil, nil
+	}
+	var firstErr error
+	modified := g.relPaths(func(p string) bool {
+		if g.keep(p) {
+			return false
+		}
+		m, err := g.repo.Modified(p)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return m
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if len(modified) == 0 {
+		return nil, nil
+	}
+	if action == config.GitModifiedAbort && !force {
+		return nil, fmt.Errorf("context %q: %w (commit or stash them, or use --force)", slug, &UncommittedChangesError{Files: modified})
+	}
+	fmt.Fprintf(os.Stderr, "Warning: switching to %q rewrites tracked files with uncommitted changes:\n", slug)
+	for _, f := range modified {
+		fmt.Fprintf(os.Stderr, "  %s\n", f)
+	}
+	return modified, nil
+}
+
+// markSkipWorktree hides the rewritten tracked files from git in
+// skip-worktree mode and returns them. Failing to run git only warns: the
+// files are already restored.
+func (g *gitGuard) markSkipWorktree() []string {
+	if g == nil || g.mode != config.GitTrackedSkipWorktree {
+		return nil
+	}
+	var paths []string
+	for _, p := range g.paths {
+		if g.repo.Tracked(p) && !g.repo.SkipWorktree(p) {
+			paths = append(paths, p)
+		}
+	}
+	if err := g.repo.SetSkipWorktree(paths); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+	return g.relPaths(func(p string) bool { return slices.Contains(paths, p) })
+}
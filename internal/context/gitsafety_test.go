@@ -0,0 +1,125 @@
+package context
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/git"
+)
+
+// setupGitProject creates a project repository with CLAUDE.md committed and
+// an untracked .claude/settings.json, saved as context "base".
+func setupGitProject(t *testing.T) (string, *config.Config) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	gitCmd := func(args ...string) {
+		args = append([]string{"-C", root, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	gitCmd("init", "-q")
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Team"), 0644)
+	gitCmd("add", "CLAUDE.md")
+	gitCmd("commit", "-q", "-m", "init")
+	os.MkdirAll(filepath.Join(root, ".claude"), 0755)
+	os.WriteFile(filepath.Join(root, ".claude", "settings.json"), []byte(`{"base": true}`), 0644)
+
+	cfg, err := config.LoadWithScope("", config.ProjectScopeAt(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Save(SaveOptions{Name: "base", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	// Make "other" the active context with different files.
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Other"), 0644)
+	os.WriteFile(filepath.Join(root, ".claude", "settings.json"), []byte(`{"other": true}`), 0644)
+	if _, err := Save(SaveOptions{Name: "other", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	SetCurrent(cfg, "other")
+	return root, cfg
+}
+
+func TestRestoreGitModified(t *testing.T) {
+	root, cfg := setupGitProject(t)
+
+	// Default: warn and report the modified tracked file.
+	result, err := Restore(RestoreOptions{Name: "base", Config: cfg, DryRun: true})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(result.TrackedModified) != 1 || result.TrackedModified[0] != "CLAUDE.md" {
+		t.Errorf("TrackedModified = %v, want [CLAUDE.md]", result.TrackedModified)
+	}
+
+	// Abort refuses unless forced.
+	cfg.Git.OnModified = config.GitModifiedAbort
+	_, err = Restore(RestoreOptions{Name: "base", Config: cfg})
+	if !errors.Is(err, ErrUncommitted) {
+		t.Fatalf("expected ErrUncommitted, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "CLAUDE.md")); string(data) != "# Other" {
+		t.Errorf("aborted restore changed CLAUDE.md: %q", data)
+	}
+	if _, err := Restore(RestoreOptions{Name: "base", Config: cfg, Force: true}); err != nil {
+		t.Fatalf("forced Restore: %v", err)
+	}
+
+	// Back at the committed content: nothing to report.
+	result, err = Restore(RestoreOptions{Name: "base", Config: cfg, DryRun: true})
+	if err != nil || len(result.TrackedModified) != 0 {
+		t.Errorf("after restore: %v, %v", result.TrackedModified, err)
+	}
+}
+
+func TestRestoreGitUntrackedOnly(t *testing.T) {
+	root, cfg := setupGitProject(t)
+	cfg.Git.Tracked = config.GitTrackedSkip
+	cfg.Git.OnModified = config.GitModifiedAbort // tracked files are left alone, so no abort
+
+	result, err := Restore(RestoreOptions{Name: "base", Config: cfg})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "CLAUDE.md")); string(data) != "# Other" {
+		t.Errorf("tracked CLAUDE.md was rewritten: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, ".claude", "settings.json")); string(data) != `{"base": true}` {
+		t.Errorf("untracked settings.json not restored: %q", data)
+	}
+	if result.FilesRestored != 1 || len(result.SkippedTracked) != 1 || result.SkippedTracked[0] != "CLAUDE.md" {
+		t.Errorf("FilesRestored = %d, SkippedTracked = %v", result.FilesRestored, result.SkippedTracked)
+	}
+}
+
+func TestRestoreGitSkipWorktree(t *testing.T) {
+	root, cfg := setupGitProject(t)
+
+	result, err := Restore(RestoreOptions{Name: "other", Config: cfg, Force: true, Tracked: config.GitTrackedSkipWorktree})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(result.SkipWorktree) != 1 || result.SkipWorktree[0] != "CLAUDE.md" {
+		t.Errorf("SkipWorktree = %v, want [CLAUDE.md]", result.SkipWorktree)
+	}
+	repo, err := git.Open(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claudeMD := filepath.Join(root, "CLAUDE.md")
+	if !repo.SkipWorktree(claudeMD) {
+		t.Error("expected CLAUDE.md to be marked skip-worktree")
+	}
+	if m, _ := repo.Modified(claudeMD); m {
+		t.Error("skip-worktree CLAUDE.md still reported as modified")
+	}
+}
@@ -0,0 +1,117 @@
+package context
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// HookEvent names a point in an operation where configured hooks run.
+type HookEvent string
+
+const (
+	HookPreSave    HookEvent = "preSave"
+	HookPostSave   HookEvent = "postSave"
+	HookPreSwitch  HookEvent = "preSwitch"
+	HookPostSwitch HookEvent = "postSwitch"
+	HookPreDelete  HookEvent = "preDelete"
+)
+
+// HookEnv describes the operation a hook is running for. It is exposed to
+// hook commands as CLAUDECTX_* environment variables.
+type HookEnv struct {
+	Context string // context being saved, switched to or deleted
+	From    string // active context before a save or switch
+	To      string // active context after a save or switch
+}
+
+// hookVars are the variables runHooks sets per operation. Values inherited
+// from the environment, e.g. by claudectx run from another hook, are dropped
+// so a hook never sees one that does not apply to its event.
+var hookVars = []string{"CLAUDECTX_EVENT", "CLAUDECTX_CONTEXT", "CLAUDECTX_FROM", "CLAUDECTX_TO"}
+
+// hookCommands returns the configured commands for an event.
+func hookCommands(h config.Hooks, event HookEvent) []string {
+	switch event {
+	case HookPreSave:
+		return h.PreSave
+	case HookPostSave:
+		return h.PostSave
+	case HookPreSwitch:
+		return h.PreSwitch
+	case HookPostSwitch:
+		return h.PostSwitch
+	case HookPreDelete:
+		return h.PreDelete
+	}
+	return nil
+}
+
+// runHooks executes the commands configured for event in order, stopping at
+// the first failure. Hook output goes to stderr so it never mixes with
+// command results on stdout.
+func runHooks(cfg *config.Config, event HookEvent, env HookEnv) error {
+	commands := hookCommands(cfg.Hooks, event)
+	if len(commands) == 0 {
+		return nil
+	}
+
+	scope := ""
+	if cfg.Scope != nil {
+		scope = string(cfg.Scope.Type)
+	}
+	var vars []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if !slices.Contains(hookVars, name) {
+			vars = append(vars, kv)
+		}
+	}
+	vars = append(vars,
+		"CLAUDECTX_EVENT="+string(event),
+		"CLAUDECTX_CONTEXT="+env.Context,
+		"CLAUDECTX_SCOPE="+scope,
+		// Not CLAUDECTX_STORAGE_DIR: that overrides the user storage of any
+		// claudectx the hook runs.
+		"CLAUDECTX_SCOPE_STORAGE_DIR="+cfg.StorageDir,
+	)
+	if event != HookPreDelete {
+		vars = append(vars, "CLAUDECTX_FROM="+env.From, "CLAUDECTX_TO="+env.To)
+	}
+
+	for _, command := range commands {
+		if err := runHookCommand(command, vars, cfg.Hooks.Timeout()); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", event, command, err)
+		}
+	}
+	return nil
+}
+
+func runHookCommand(command string, env []string, timeout time.Duration) error {
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = env
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), gocontext.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
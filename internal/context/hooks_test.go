@@ -0,0 +1,162 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use POSIX shell commands")
+	}
+}
+
+func TestPreSwitchHookVetoes(t *testing.T) {
+	skipOnWindows(t)
+	cfg := newProjectTestConfig(t)
+	claudeMDPath := cfg.Scope.ExtraFileByTag("claudemd").Path
+
+	saveProjectContext(t, cfg, "ctx-a", map[string]string{"CLAUDE.md": "# A"})
+	saveProjectContext(t, cfg, "ctx-b", map[string]string{"CLAUDE.md": "# B"})
+
+	cfg.Hooks.PreSwitch = config.HookCommands{"exit 3"}
+	_, err := Restore(RestoreOptions{Name: "ctx-a", Config: cfg})
+	if err == nil || !strings.Contains(err.Error(), "preSwitch hook") {
+		t.Fatalf("expected preSwitch veto error, got %v", err)
+	}
+
+	current, _ := GetCurrent(cfg)
+	if current != "ctx-b" {
+		t.Errorf("expected current to stay ctx-b, got %q", current)
+	}
+	data, _ := os.ReadFile(claudeMDPath)
+	if string(data) != "# B" {
+		t.Errorf("expected live files untouched, got %q", data)
+	}
+}
+
+func TestPostSwitchHookReceivesEnv(t *testing.T) {
+	skipOnWindows(t)
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ctx-a", map[string]string{"CLAUDE.md": "# A"})
+	saveProjectContext(t, cfg, "ctx-b", map[string]string{"CLAUDE.md": "# B"})
+
+	out := filepath.Join(t.TempDir(), "env.txt")
+	cfg.Hooks.PostSwitch = config.HookCommands{
+		`echo "$CLAUDECTX_EVENT $CLAUDECTX_FROM $CLAUDECTX_TO $CLAUDECTX_SCOPE" > ` + out,
+	}
+	if _, err := Restore(RestoreOptions{Name: "ctx-a", Config: cfg}); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected hook to run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "postSwitch ctx-b ctx-a project" {
+		t.Errorf("unexpected hook env: %q", got)
+	}
+}
+
+func TestHookRunsClaudectxWithUserStorage(t *testing.T) {
+	skipOnWindows(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.HomeDirEnv, "")
+	t.Setenv(config.StorageDirEnv, "")
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ctx-a", map[string]string{"CLAUDE.md": "# A"})
+	saveProjectContext(t, cfg, "ctx-b", map[string]string{"CLAUDE.md": "# B"})
+
+	out := filepath.Join(t.TempDir(), "env.txt")
+	cfg.Hooks.PostSwitch = config.HookCommands{"env > " + out}
+	if _, err := Restore(RestoreOptions{Name: "ctx-a", Config: cfg}); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected hook to run: %v", err)
+	}
+
+	// Resolve user storage the way a claudectx started by the hook would.
+	for _, line := range strings.Split(string(data), "\n") {
+		if name, value, ok := strings.Cut(line, "="); ok && strings.HasPrefix(name, "CLAUDECTX_") {
+			t.Setenv(name, value)
+		}
+	}
+	if got := os.Getenv("CLAUDECTX_SCOPE_STORAGE_DIR"); got != cfg.StorageDir {
+		t.Errorf("CLAUDECTX_SCOPE_STORAGE_DIR = %q, want %q", got, cfg.StorageDir)
+	}
+	storage, err := config.DefaultStorageDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage != filepath.Join(home, ".claudectx") {
+		t.Errorf("claudectx in a project hook uses user storage %s, want %s", storage, filepath.Join(home, ".claudectx"))
+	}
+}
+
+func TestPostHookFailureDoesNotAbort(t *testing.T) {
+	skipOnWindows(t)
+	cfg := newProjectTestConfig(t)
+	cfg.Hooks.PostSave = config.HookCommands{"exit 1"}
+
+	saveProjectContext(t, cfg, "ctx-a", map[string]string{"CLAUDE.md": "# A"})
+	if !ContextExists(cfg.ContextsDir(), "ctx-a") {
+		t.Error("expected save to succeed despite failing postSave hook")
+	}
+}
+
+func TestPreSaveHookVetoes(t *testing.T) {
+	skipOnWindows(t)
+	cfg := newProjectTestConfig(t)
+	cfg.Hooks.PreSave = config.HookCommands{"false"}
+
+	os.WriteFile(cfg.Scope.ExtraFileByTag("claudemd").Path, []byte("# A"), 0644)
+	if _, err := Save(SaveOptions{Name: "ctx-a", Config: cfg}); err == nil {
+		t.Fatal("expected preSave veto error")
+	}
+	if ContextExists(cfg.ContextsDir(), "ctx-a") {
+		t.Error("expected no context to be saved")
+	}
+}
+
+func TestPreDeleteHookVetoes(t *testing.T) {
+	skipOnWindows(t)
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ctx-a", map[string]string{"CLAUDE.md": "# A"})
+
+	t.Setenv("CLAUDECTX_FROM", "inherited")
+	cfg.Hooks.PreDelete = config.HookCommands{`test "$CLAUDECTX_CONTEXT" != ctx-a && test -z "${CLAUDECTX_FROM+set}${CLAUDECTX_TO+set}"`}
+	if err := DeleteContext(cfg, "ctx-a"); err == nil {
+		t.Fatal("expected preDelete veto error")
+	}
+	if !ContextExists(cfg.ContextsDir(), "ctx-a") {
+		t.Error("expected context to survive vetoed delete")
+	}
+
+	// Only CLAUDECTX_CONTEXT names the context; FROM and TO are unset.
+	saveProjectContext(t, cfg, "ctx-b", map[string]string{"CLAUDE.md": "# B"})
+	if err := DeleteContext(cfg, "ctx-b"); err != nil {
+		t.Errorf("delete with preDelete hook: %v", err)
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	skipOnWindows(t)
+	cfg := newProjectTestConfig(t)
+	cfg.Hooks.PreSave = config.HookCommands{"exec sleep 5"}
+	cfg.Hooks.TimeoutSeconds = 1
+
+	os.WriteFile(cfg.Scope.ExtraFileByTag("claudemd").Path, []byte("# A"), 0644)
+	_, err := Save(SaveOptions{Name: "ctx-a", Config: cfg})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
@@ -146,7 +146,7 @@ func TestSaveModifyRestoreRoundTrip(t *testing.T) {
 	}
 
 	// 7. Delete context
-	if err := DeleteContext(cfg.ContextsDir(), "test-ctx"); err != nil {
+	if err := DeleteContext(cfg, "test-ctx"); err != nil {
 		t.Fatal(err)
 	}
 	names, _ = ListContexts(cfg.ContextsDir())
@@ -284,11 +284,241 @@ func TestProjectScopeRoundTrip(t *testing.T) {
 	}
 
 	// 6. Delete context
-	if err := DeleteContext(cfg.ContextsDir(), "proj-ctx"); err != nil {
+	if err := DeleteContext(cfg, "proj-ctx"); err != nil {
 		t.Fatal(err)
 	}
 }
 
+// TestLocalScopeRoundTrip verifies that project and local scopes split a
+// project's files: switching one never touches the other's files.
+func TestLocalScopeRoundTrip(t *testing.T) {
+	projectRoot := t.TempDir()
+	dotClaudeDir := filepath.Join(projectRoot, ".claude")
+	os.MkdirAll(dotClaudeDir, 0755)
+
+	settingsPath := filepath.Join(dotClaudeDir, "settings.json")
+	localSettingsPath := filepath.Join(dotClaudeDir, "settings.local.json")
+	localMDPath := filepath.Join(projectRoot, "CLAUDE.local.md")
+	os.WriteFile(settingsPath, []byte(`{"team": true}`), 0644)
+	os.WriteFile(localSettingsPath, []byte(`{"mine": 1}`), 0644)
+	os.WriteFile(filepath.Join(projectRoot, "CLAUDE.md"), []byte("# Team"), 0644)
+	os.WriteFile(localMDPath, []byte("# Mine 1"), 0644)
+
+	projectCfg, err := config.LoadWithScope("", config.ProjectScopeAt(projectRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	localCfg, err := config.LoadWithScope("", config.LocalScopeAt(projectRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if projectCfg.ContextsDir() == localCfg.ContextsDir() {
+		t.Fatalf("project and local scopes share %s", localCfg.ContextsDir())
+	}
+
+	// 1. Project snapshots exclude the personal files, local ones hold only them.
+	result, err := Save(SaveOptions{Name: "team", Config: projectCfg})
+	if err != nil {
+		t.Fatalf("Save project: %v", err)
+	}
+	if result.Files != 2 {
+		t.Errorf("expected 2 project files (settings.json + CLAUDE.md), got %d", result.Files)
+	}
+	result, err = Save(SaveOptions{Name: "mine-1", Config: localCfg})
+	if err != nil {
+		t.Fatalf("Save local: %v", err)
+	}
+	if result.Files != 2 {
+		t.Errorf("expected 2 local files (settings.local.json + CLAUDE.local.md), got %d", result.Files)
+	}
+	m, err := ReadManifest(filepath.Join(localCfg.ContextsDir(), "mine-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Scope != "local" {
+		t.Errorf("expected scope 'local', got %q", m.Scope)
+	}
+
+	// 2. Switch local variants; the team settings must not change.
+	os.WriteFile(localSettingsPath, []byte(`{"mine": 2}`), 0644)
+	os.WriteFile(localMDPath, []byte("# Mine 2"), 0644)
+	if _, err := Save(SaveOptions{Name: "mine-2", Config: localCfg}); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(settingsPath, []byte(`{"team": "edited"}`), 0644)
+
+	if _, err := Restore(RestoreOptions{Name: "mine-1", Config: localCfg}); err != nil {
+		t.Fatalf("Restore local: %v", err)
+	}
+	if data, _ := os.ReadFile(localSettingsPath); string(data) != `{"mine": 1}` {
+		t.Errorf("settings.local.json not restored: %q", data)
+	}
+	if data, _ := os.ReadFile(localMDPath); string(data) != "# Mine 1" {
+		t.Errorf("CLAUDE.local.md not restored: %q", data)
+	}
+	if data, _ := os.ReadFile(settingsPath); string(data) != `{"team": "edited"}` {
+		t.Errorf("local restore touched settings.json: %q", data)
+	}
+
+	// 3. Switching the project scope keeps the personal files.
+	if _, err := Restore(RestoreOptions{Name: "team", Config: projectCfg}); err != nil {
+		t.Fatalf("Restore project: %v", err)
+	}
+	if data, _ := os.ReadFile(settingsPath); string(data) != `{"team": true}` {
+		t.Errorf("settings.json not restored: %q", data)
+	}
+	if data, _ := os.ReadFile(localSettingsPath); string(data) != `{"mine": 1}` {
+		t.Errorf("project restore touched settings.local.json: %q", data)
+	}
+	if data, _ := os.ReadFile(localMDPath); string(data) != "# Mine 1" {
+		t.Errorf("project restore touched CLAUDE.local.md: %q", data)
+	}
+}
+
+// TestProjectRestoreSkipsLegacyLocalSettings verifies that a project context
+// saved before the local scope existed, or under custom exclude patterns,
+// never overwrites the personal settings.local.json.
+func TestProjectRestoreSkipsLegacyLocalSettings(t *testing.T) {
+	projectRoot := t.TempDir()
+	dotClaudeDir := filepath.Join(projectRoot, ".claude")
+	os.MkdirAll(dotClaudeDir, 0755)
+	localSettingsPath := filepath.Join(dotClaudeDir, "settings.local.json")
+	os.WriteFile(filepath.Join(dotClaudeDir, "settings.json"), []byte(`{"team": true}`), 0644)
+	os.WriteFile(localSettingsPath, []byte(`{"mine": "live"}`), 0644)
+
+	// A project config replacing the default exclude patterns.
+	scope := config.ProjectScopeAt(projectRoot)
+	os.MkdirAll(scope.StorageDir, 0755)
+	os.WriteFile(scope.ConfigFile(), []byte(`{"excludePatterns": ["**/.DS_Store"]}`), 0644)
+	cfg, err := config.LoadWithScope("", scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Save(SaveOptions{Name: "team", Config: cfg})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if result.Files != 1 {
+		t.Errorf("expected only settings.json saved, got %d files", result.Files)
+	}
+
+	// Simulate a legacy snapshot that still holds the personal settings.
+	contextDir := filepath.Join(cfg.ContextsDir(), "team")
+	m, err := ReadManifest(contextDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := []byte(`{"mine": "legacy"}`)
+	os.WriteFile(filepath.Join(contextDir, "dotclaude", "settings.local.json"), legacy, 0644)
+	sum, _ := FileChecksum(filepath.Join(contextDir, "dotclaude", "settings.local.json"))
+	m.Files = append(m.Files, FileEntry{RelPath: "dotclaude/settings.local.json", Size: int64(len(legacy)), Mode: 0644, Checksum: sum, Source: "dotclaude"})
+	m.Checksum = ManifestChecksum(m.Files)
+	if err := WriteManifest(contextDir, m); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Restore(RestoreOptions{Name: "team", Config: cfg, Force: true}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if data, _ := os.ReadFile(localSettingsPath); string(data) != `{"mine": "live"}` {
+		t.Errorf("project restore overwrote settings.local.json: %q", data)
+	}
+	drift, err := Drift(cfg, "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !drift.Clean() {
+		t.Errorf("expected no drift from the reserved entry, got %+v", drift)
+	}
+}
+
+// TestManagedScopeRoundTrip verifies switching managed policy files.
+func TestManagedScopeRoundTrip(t *testing.T) {
+	tmp := t.TempDir()
+	settingsDir := filepath.Join(tmp, "claude-code")
+	os.MkdirAll(settingsDir, 0755)
+	policyPath := filepath.Join(settingsDir, "managed-settings.json")
+	os.WriteFile(policyPath, []byte(`{"permissions": {"deny": ["WebFetch"]}}`), 0644)
+	os.WriteFile(filepath.Join(settingsDir, "notes.txt"), []byte("not managed"), 0644)
+
+	cfg, err := config.LoadWithScope("", config.ManagedScopeAt(settingsDir, filepath.Join(tmp, "storage")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Save(SaveOptions{Name: "strict", Config: cfg})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if result.Files != 1 {
+		t.Errorf("expected only managed-settings.json to be saved, got %d files", result.Files)
+	}
+
+	os.WriteFile(policyPath, []byte(`{}`), 0644)
+	if _, err := Save(SaveOptions{Name: "open", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(RestoreOptions{Name: "strict", Config: cfg}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if data, _ := os.ReadFile(policyPath); string(data) != `{"permissions": {"deny": ["WebFetch"]}}` {
+		t.Errorf("managed-settings.json not restored: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(settingsDir, "notes.txt")); err != nil {
+		t.Errorf("unmanaged file removed: %v", err)
+	}
+}
+
+// TestCustomScopeRoundTrip verifies that extra files with custom tags are
+// saved and restored.
+func TestCustomScopeRoundTrip(t *testing.T) {
+	tmp := t.TempDir()
+	promptsDir := filepath.Join(tmp, "prompts")
+	os.MkdirAll(promptsDir, 0755)
+	os.WriteFile(filepath.Join(promptsDir, "review.md"), []byte("v1"), 0644)
+	guidePath := filepath.Join(tmp, "TEAM.md")
+	os.WriteFile(guidePath, []byte("guide v1"), 0644)
+
+	storageDir := filepath.Join(tmp, "storage")
+	scope := &config.Scope{
+		Type:            "prompts",
+		DotClaudeDir:    promptsDir,
+		ExtraFiles:      []config.ExtraFile{{Path: guidePath, Tag: "teamguide"}},
+		StorageDir:      storageDir,
+		IncludePatterns: config.DefaultProjectIncludePatterns,
+		ExcludePatterns: config.DefaultProjectExcludePatterns,
+	}
+	cfg, err := config.LoadWithScope("", scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Save(SaveOptions{Name: "v1", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadManifest(filepath.Join(cfg.ContextsDir(), "v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Scope != "prompts" {
+		t.Errorf("Scope = %q, want prompts", m.Scope)
+	}
+
+	os.WriteFile(filepath.Join(promptsDir, "review.md"), []byte("v2"), 0644)
+	os.WriteFile(guidePath, []byte("guide v2"), 0644)
+	if _, err := Save(SaveOptions{Name: "v2", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(RestoreOptions{Name: "v1", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(guidePath); string(data) != "guide v1" {
+		t.Errorf("TEAM.md not restored: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(promptsDir, "review.md")); string(data) != "v1" {
+		t.Errorf("review.md not restored: %q", data)
+	}
+}
+
 // TestCrossScopeRestoreBlocked verifies that restoring a context saved with
 // one scope into a different scope produces an error.
 func TestCrossScopeRestoreBlocked(t *testing.T) {
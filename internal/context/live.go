@@ -0,0 +1,59 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
+)
+
+// liveFile is a managed file in the live scope.
+type liveFile struct {
+	RelPath string // path as stored inside a context ("CLAUDE.md", "dotclaude/settings.json")
+	Source  string
+	AbsPath string
+	Info    os.FileInfo
+}
+
+// collectLiveFiles returns the scope's extra files that exist followed by the
+// filtered files in the .claude/ directory.
+func collectLiveFiles(cfg *config.Config) ([]liveFile, error) {
+	scope := cfg.Scope
+	var files []liveFile
+
+	for _, ef := range scope.ExtraFiles {
+		info, err := os.Stat(ef.Path)
+		if err != nil {
+			continue // file doesn't exist, skip
+		}
+		files = append(files, liveFile{
+			RelPath: filepath.Base(ef.Path),
+			Source:  ef.Tag,
+			AbsPath: ef.Path,
+			Info:    info,
+		})
+	}
+
+	if _, err := os.Stat(scope.DotClaudeDir); err == nil {
+		walked, err := fileutil.WalkFiltered(
+			scope.DotClaudeDir,
+			cfg.IncludePatterns,
+			cfg.ExcludePatterns,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("walk .claude: %w", err)
+		}
+		for _, w := range walked {
+			files = append(files, liveFile{
+				RelPath: toSlash(filepath.Join("dotclaude", w.RelPath)),
+				Source:  "dotclaude",
+				AbsPath: w.AbsPath,
+				Info:    w.Info,
+			})
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,34 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// lockedError is returned when an operation would modify a locked context.
+func lockedError(slug string) error {
+	return fmt.Errorf("context %q is %w; unlock it with 'claudectx unlock %s'", slug, ErrLocked, slug)
+}
+
+// IsLocked reports whether a saved context is marked read-only.
+func IsLocked(cfg *config.Config, slug string) bool {
+	m, err := ReadManifest(filepath.Join(cfg.ContextsDir(), slug))
+	return err == nil && m.Locked
+}
+
+// SetLocked marks a context read-only (or writable again). Locked contexts
+// are never overwritten by auto-save, Save, rename or delete.
+func SetLocked(cfg *config.Config, slug string, locked bool) error {
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	m, err := ReadManifest(contextDir)
+	if err != nil {
+		return fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
+	}
+	if m.Locked == locked {
+		return nil
+	}
+	m.Locked = locked
+	return WriteManifest(contextDir, m)
+}
@@ -0,0 +1,103 @@
+package context
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLockedContextRefusesChanges(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "other", map[string]string{"CLAUDE.md": "# Other"})
+	saveProjectContext(t, cfg, "frozen", map[string]string{"CLAUDE.md": "# Frozen"})
+	if err := SetLocked(cfg, "frozen", true); err != nil {
+		t.Fatal(err)
+	}
+	if !IsLocked(cfg, "frozen") {
+		t.Fatal("expected context to be locked")
+	}
+
+	if _, err := Save(SaveOptions{Name: "frozen", Overwrite: true, Config: cfg}); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("expected Save(Overwrite) to be refused, got %v", err)
+	}
+	if err := RenameContext(cfg, "frozen", "thawed"); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("expected rename to be refused, got %v", err)
+	}
+	if err := DeleteContext(cfg, "frozen"); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("expected delete to be refused, got %v", err)
+	}
+
+	if err := SetLocked(cfg, "frozen", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteContext(cfg, "frozen"); err != nil {
+		t.Errorf("expected delete after unlock to succeed, got %v", err)
+	}
+}
+
+func TestAutoSaveLockedContext(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "target", map[string]string{"CLAUDE.md": "# Target"})
+	saveProjectContext(t, cfg, "frozen", map[string]string{"CLAUDE.md": "# Frozen"})
+	SetLocked(cfg, "frozen", true)
+	os.WriteFile(cfg.Scope.ExtraFileByTag("claudemd").Path, []byte("# Edited"), 0644)
+
+	var question string
+	cfg.Confirm = func(q string) bool {
+		question = q
+		return true
+	}
+	AutoSaveCurrent(cfg, "target")
+
+	drift, err := Drift(cfg, "frozen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift.Modified) != 1 || drift.Modified[0] != "CLAUDE.md" {
+		t.Errorf("expected locked snapshot untouched (CLAUDE.md drifted), got %+v", drift)
+	}
+	if !strings.Contains(question, "frozen-edits-") {
+		t.Fatalf("expected offer to save edits, got %q", question)
+	}
+
+	names, _ := ListContexts(cfg.ContextsDir())
+	var edits string
+	for _, n := range names {
+		if strings.HasPrefix(n, "frozen-edits-") {
+			edits = n
+		}
+	}
+	if edits == "" {
+		t.Fatalf("expected an edits context, got %v", names)
+	}
+	if d, _ := Drift(cfg, edits); !d.Clean() {
+		t.Errorf("expected edits context to match live files, got %+v", d)
+	}
+}
+
+func TestRenameContext(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "old", map[string]string{"CLAUDE.md": "v1"})
+	os.WriteFile(cfg.Scope.ExtraFileByTag("claudemd").Path, []byte("v2"), 0644)
+	if _, err := Save(SaveOptions{Name: "old", Overwrite: true, KeepRevision: true, Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RenameContext(cfg, "old", "new"); err != nil {
+		t.Fatalf("RenameContext failed: %v", err)
+	}
+	if ContextExists(cfg.ContextsDir(), "old") || !ContextExists(cfg.ContextsDir(), "new") {
+		t.Fatal("expected context directory to be renamed")
+	}
+	if current, _ := GetCurrent(cfg); current != "new" {
+		t.Errorf("expected current marker to follow rename, got %q", current)
+	}
+	if revs, _ := ListRevisions(cfg, "new"); len(revs) != 1 {
+		t.Errorf("expected revisions to move with the context, got %d", len(revs))
+	}
+
+	saveProjectContext(t, cfg, "other", map[string]string{"CLAUDE.md": "x"})
+	if err := RenameContext(cfg, "new", "other"); err == nil {
+		t.Error("expected rename onto an existing context to fail")
+	}
+}
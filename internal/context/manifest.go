@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
 )
 
 // ReadManifest reads the manifest.json from a context directory.
@@ -88,11 +92,108 @@ func ContextExists(contextsDir, name string) bool {
 	return err == nil
 }
 
-// DeleteContext removes a saved context directory.
-func DeleteContext(contextsDir, name string) error {
-	dir := filepath.Join(contextsDir, name)
+// DeleteContext removes a saved context directory after running any
+// preDelete hooks, which may veto the deletion.
+func DeleteContext(cfg *config.Config, name string) error {
+	dir := filepath.Join(cfg.ContextsDir(), name)
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return fmt.Errorf("context %q not found", name)
+		return fmt.Errorf("context %q %w", name, ErrNotFound)
+	}
+	if IsLocked(cfg, name) {
+		return lockedError(name)
+	}
+	if err := runHooks(cfg, HookPreDelete, HookEnv{Context: name}); err != nil {
+		return err
 	}
 	return os.RemoveAll(dir)
 }
+
+// RenameContext renames a saved context, its revisions and, if it is active,
+// the current marker.
+func RenameContext(cfg *config.Config, oldName, newName string) error {
+	oldSlug := Slugify(oldName)
+	newSlug := Slugify(newName)
+	if newSlug == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidName, newName)
+	}
+	if !ContextExists(cfg.ContextsDir(), oldSlug) {
+		return fmt.Errorf("context %q %w", oldSlug, ErrNotFound)
+	}
+	if ContextExists(cfg.ContextsDir(), newSlug) {
+		return fmt.Errorf("context %q %w", newSlug, ErrExists)
+	}
+	if IsLocked(cfg, oldSlug) {
+		return lockedError(oldSlug)
+	}
+
+	oldDir := filepath.Join(cfg.ContextsDir(), oldSlug)
+	newDir := filepath.Join(cfg.ContextsDir(), newSlug)
+	if err := os.Rename(oldDir, newDir); err != nil {
+		return fmt.Errorf("rename context: %w", err)
+	}
+
+	m, err := ReadManifest(newDir)
+	if err != nil {
+		return err
+	}
+	m.Name = newSlug
+	if err := WriteManifest(newDir, m); err != nil {
+		return err
+	}
+
+	oldRevs := revisionsDirFor(cfg, oldSlug)
+	if _, err := os.Stat(oldRevs); err == nil {
+		if err := os.Rename(oldRevs, revisionsDirFor(cfg, newSlug)); err != nil {
+			return fmt.Errorf("rename revisions: %w", err)
+		}
+	}
+
+	if current, _ := GetCurrent(cfg); current == oldSlug {
+		return SetCurrent(cfg, newSlug)
+	}
+	return nil
+}
+
+// CopyContext duplicates a saved context under a new name. The copy starts
+// unlocked and without revisions.
+func CopyContext(cfg *config.Config, srcName, dstName string) error {
+	srcSlug := Slugify(srcName)
+	dstSlug := Slugify(dstName)
+	if dstSlug == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidName, dstName)
+	}
+	if !ContextExists(cfg.ContextsDir(), srcSlug) {
+		return fmt.Errorf("context %q %w", srcSlug, ErrNotFound)
+	}
+	if ContextExists(cfg.ContextsDir(), dstSlug) {
+		return fmt.Errorf("context %q %w", dstSlug, ErrExists)
+	}
+
+	tmpDir, err := os.MkdirTemp(cfg.ContextsDir(), ".copy-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	os.Chmod(tmpDir, 0755)
+	if err := fileutil.CopyDir(filepath.Join(cfg.ContextsDir(), srcSlug), tmpDir); err != nil {
+		return fmt.Errorf("copy context: %w", err)
+	}
+
+	m, err := ReadManifest(tmpDir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	m.Name = dstSlug
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	m.Locked = false
+	if err := WriteManifest(tmpDir, m); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpDir, filepath.Join(cfg.ContextsDir(), dstSlug)); err != nil {
+		return fmt.Errorf("rename copied context: %w", err)
+	}
+	return nil
+}
@@ -3,8 +3,11 @@ package context
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
 )
 
 func TestWriteAndReadManifest(t *testing.T) {
@@ -92,16 +95,89 @@ func TestListContexts(t *testing.T) {
 }
 
 func TestDeleteContext(t *testing.T) {
-	dir := t.TempDir()
-	ctxDir := filepath.Join(dir, "test")
+	cfg := &config.Config{StorageDir: t.TempDir()}
+	ctxDir := filepath.Join(cfg.ContextsDir(), "test")
 	os.MkdirAll(ctxDir, 0755)
 	now := time.Now()
 	WriteManifest(ctxDir, &Manifest{Name: "test", CreatedAt: now, UpdatedAt: now})
 
-	if err := DeleteContext(dir, "test"); err != nil {
+	if err := DeleteContext(cfg, "test"); err != nil {
 		t.Fatal(err)
 	}
 	if _, err := os.Stat(ctxDir); !os.IsNotExist(err) {
 		t.Error("expected context to be deleted")
 	}
 }
+
+func TestCopyContext(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "src", map[string]string{"CLAUDE.md": "# Src"})
+	SetLocked(cfg, "src", true)
+
+	if err := CopyContext(cfg, "src", "dst"); err != nil {
+		t.Fatalf("CopyContext failed: %v", err)
+	}
+	m, err := ReadManifest(filepath.Join(cfg.ContextsDir(), "dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "dst" || m.Locked || len(m.Files) != 1 {
+		t.Errorf("unexpected copied manifest: %+v", m)
+	}
+	assertContextDirMode(t, cfg, "dst")
+	if err := CopyContext(cfg, "src", "dst"); err == nil {
+		t.Error("expected copy onto an existing context to fail")
+	}
+}
+
+// assertContextDirMode checks that a context dir has the 0755 mode Save
+// gives it, not the 0700 of a temp dir it was renamed from.
+func assertContextDirMode(t *testing.T, cfg *config.Config, slug string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(filepath.Join(cfg.ContextsDir(), slug))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("context %q dir mode = %v, want 0755", slug, info.Mode().Perm())
+	}
+}
+
+func TestCurrentMarker(t *testing.T) {
+	cfg := &config.Config{StorageDir: filepath.Join(t.TempDir(), "storage")}
+
+	if name, err := GetCurrent(cfg); err != nil || name != "" {
+		t.Errorf("no marker: got %q, %v", name, err)
+	}
+	if err := SetCurrent(cfg, "work"); err != nil {
+		t.Fatal(err)
+	}
+	if name, err := GetCurrent(cfg); err != nil || name != "work" {
+		t.Errorf("got %q, %v; want work", name, err)
+	}
+
+	// An empty marker (e.g. written by another tool) must not panic.
+	os.WriteFile(cfg.CurrentFile(), nil, 0644)
+	if name, err := GetCurrent(cfg); err != nil || name != "" {
+		t.Errorf("empty marker: got %q, %v", name, err)
+	}
+}
+
+func TestCurrentMarkerInStateDir(t *testing.T) {
+	tmp := t.TempDir()
+	stateDir := filepath.Join(tmp, "worktree", ".claudectx")
+	cfg := &config.Config{
+		StorageDir: filepath.Join(tmp, "main", ".claudectx"),
+		Scope:      &config.Scope{StateDir: stateDir},
+	}
+
+	if err := SetCurrent(cfg, "work"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, "current")); err != nil {
+		t.Errorf("marker not written to the state dir: %v", err)
+	}
+}
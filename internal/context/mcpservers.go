@@ -0,0 +1,47 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// mcpServerFiles are the stored files that may configure MCP servers under a
+// top-level "mcpServers" object.
+var mcpServerFiles = []string{".mcp.json", ".claude.json"}
+
+// MCPServers returns the sorted names of the MCP servers configured in a
+// saved context. Files that are missing or not valid JSON are ignored.
+func MCPServers(cfg *config.Config, slug string) ([]string, error) {
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	if _, err := os.Stat(contextDir); err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
+	}
+
+	seen := map[string]bool{}
+	names := []string{}
+	for _, name := range mcpServerFiles {
+		data, err := os.ReadFile(filepath.Join(contextDir, name))
+		if err != nil {
+			continue
+		}
+		var doc struct {
+			MCPServers map[string]json.RawMessage `json:"mcpServers"`
+		}
+		if json.Unmarshal(data, &doc) != nil {
+			continue
+		}
+		for server := range doc.MCPServers {
+			if !seen[server] {
+				seen[server] = true
+				names = append(names, server)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
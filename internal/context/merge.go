@@ -0,0 +1,455 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// Merge sides returned by a MergeOptions.Resolve callback.
+const (
+	MergeSideA = "a"
+	MergeSideB = "b"
+)
+
+// MergeOptions configures a merge of two contexts into a new one.
+type MergeOptions struct {
+	A           string
+	B           string
+	Base        string // optional common ancestor context; detected from revisions if empty
+	Into        string
+	Description string
+	DryRun      bool
+	Config      *config.Config
+
+	// Resolve is called for every conflict and returns MergeSideA, MergeSideB,
+	// or "" to leave it unresolved. Unresolved text conflicts are written with
+	// conflict markers; unresolved JSON key conflicts abort the merge.
+	Resolve func(c MergeConflict) string
+}
+
+// MergeConflict describes a change made differently on both sides.
+type MergeConflict struct {
+	RelPath string `json:"relPath"`
+	Key     string `json:"key,omitempty"` // dotted JSON key path; empty for whole-file conflicts
+	A       string `json:"a"`             // rendered value on side A ("" if absent)
+	B       string `json:"b"`             // rendered value on side B ("" if absent)
+	InA     bool   `json:"inA"`
+	InB     bool   `json:"inB"`
+}
+
+// MergeResult holds the result of a merge operation.
+type MergeResult struct {
+	Name      string          `json:"name"`
+	Base      string          `json:"base,omitempty"` // common ancestor used, e.g. "ours@20240101-120000.000"
+	Dir       string          `json:"dir,omitempty"`
+	Files     int             `json:"files"`
+	TotalSize int64           `json:"totalSize"`
+	Resolved  []MergeConflict `json:"resolved,omitempty"` // conflicts settled by the Resolve callback
+	Marked    []MergeConflict `json:"marked,omitempty"`   // text conflicts written with conflict markers
+}
+
+// UnresolvedConflictsError is returned when JSON key conflicts remain after
+// calling the resolver.
+type UnresolvedConflictsError struct {
+	Conflicts []MergeConflict
+}
+
+func (e *UnresolvedConflictsError) Error() string {
+	var lines []string
+	for _, c := range e.Conflicts {
+		if c.Key != "" {
+			lines = append(lines, fmt.Sprintf("  %s: %s", c.RelPath, c.Key))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s", c.RelPath))
+		}
+	}
+	return fmt.Sprintf("%d unresolved merge conflicts:\n%s", len(e.Conflicts), strings.Join(lines, "\n"))
+}
+
+// Is makes errors.Is(err, ErrConflict) match.
+func (e *UnresolvedConflictsError) Is(target error) bool { return target == ErrConflict }
+
+// mergeSide holds a context's manifest and directory for merging.
+type mergeSide struct {
+	dir      string
+	manifest *Manifest
+	entries  map[string]FileEntry
+}
+
+func newMergeSide(dir string, m *Manifest) *mergeSide {
+	entries := make(map[string]FileEntry, len(m.Files))
+	for _, f := range m.Files {
+		entries[f.RelPath] = f
+	}
+	return &mergeSide{dir: dir, manifest: m, entries: entries}
+}
+
+func loadMergeSide(cfg *config.Config, name string) (*mergeSide, error) {
+	slug := Slugify(name)
+	dir := filepath.Join(cfg.ContextsDir(), slug)
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
+	}
+	return newMergeSide(dir, m), nil
+}
+
+// commonRevision returns the most recent snapshot found in the history of
+// both contexts: a revision of one that is identical to a revision or the
+// current snapshot of the other. Of the matching snapshots on either side,
+// the one saved last (ties broken by label) is used, so the result does not
+// depend on argument order. It returns nil if they share no snapshot.
+func commonRevision(cfg *config.Config, a, b *mergeSide) (*mergeSide, string) {
+	type candidate struct {
+		label string
+		dir   string
+		m     *Manifest
+		rev   bool
+	}
+	history := func(side *mergeSide) []candidate {
+		slug := filepath.Base(side.dir)
+		list := []candidate{{label: slug, dir: side.dir, m: side.manifest}}
+		revs, _ := ListRevisions(cfg, slug)
+		for _, r := range revs {
+			list = append(list, candidate{label: slug + "@" + r.ID, dir: r.Dir, m: r.Manifest, rev: true})
+		}
+		return list
+	}
+	histA, histB := history(a), history(b)
+
+	// A checksum is shared if both histories hold it and at least one of
+	// the snapshots holding it is a revision.
+	inA, inB, inRev := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, c := range histA {
+		inA[c.m.Checksum] = true
+		inRev[c.m.Checksum] = inRev[c.m.Checksum] || c.rev
+	}
+	for _, c := range histB {
+		inB[c.m.Checksum] = true
+		inRev[c.m.Checksum] = inRev[c.m.Checksum] || c.rev
+	}
+
+	var best *candidate
+	for _, c := range append(histA, histB...) {
+		sum := c.m.Checksum
+		if !inA[sum] || !inB[sum] || !inRev[sum] {
+			continue
+		}
+		if best == nil || c.m.UpdatedAt.After(best.m.UpdatedAt) ||
+			(c.m.UpdatedAt.Equal(best.m.UpdatedAt) && c.label < best.label) {
+			pick := c
+			best = &pick
+		}
+	}
+	if best == nil {
+		return nil, ""
+	}
+	return newMergeSide(best.dir, best.m), best.label
+}
+
+func (s *mergeSide) read(relPath string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(relPath)))
+}
+
+// Merge combines contexts A and B into a new context. Files are merged at
+// file level; JSON objects are merged key by key. The common ancestor used to
+// decide which side changed a file or key is Base when set, otherwise the
+// most recent snapshot shared by the revisions of A and B. Without either,
+// any difference between A and B is a conflict.
+func Merge(opts MergeOptions) (*MergeResult, error) {
+	cfg := opts.Config
+	into := Slugify(opts.Into)
+	if into == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, opts.Into)
+	}
+	if ContextExists(cfg.ContextsDir(), into) {
+		return nil, fmt.Errorf("context %q %w", into, ErrExists)
+	}
+
+	a, err := loadMergeSide(cfg, opts.A)
+	if err != nil {
+		return nil, err
+	}
+	b, err := loadMergeSide(cfg, opts.B)
+	if err != nil {
+		return nil, err
+	}
+	if a.manifest.Scope != b.manifest.Scope {
+		return nil, fmt.Errorf("%w: cannot merge %s-scope context %q with %s-scope context %q",
+			ErrScopeMismatch, a.manifest.Scope, a.manifest.Name, b.manifest.Scope, b.manifest.Name)
+	}
+	base := &mergeSide{entries: map[string]FileEntry{}}
+	baseLabel := ""
+	if opts.Base != "" {
+		if base, err = loadMergeSide(cfg, opts.Base); err != nil {
+			return nil, err
+		}
+		baseLabel = Slugify(opts.Base)
+	} else if found, label := commonRevision(cfg, a, b); found != nil {
+		base, baseLabel = found, label
+	}
+
+	paths := map[string]bool{}
+	for p := range a.entries {
+		paths[p] = true
+	}
+	for p := range b.entries {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	resolve := opts.Resolve
+	if resolve == nil {
+		resolve = func(MergeConflict) string { return "" }
+	}
+
+	result := &MergeResult{Name: into, Base: baseLabel}
+	var unresolved []MergeConflict
+	var outputs []pendingFile
+
+	for _, relPath := range sorted {
+		out, conflicts, err := mergeFile(relPath, a, b, base, resolve, result)
+		if err != nil {
+			return nil, err
+		}
+		unresolved = append(unresolved, conflicts...)
+		if out != nil {
+			outputs = append(outputs, *out)
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return nil, &UnresolvedConflictsError{Conflicts: unresolved}
+	}
+
+	for _, out := range outputs {
+		result.Files++
+		result.TotalSize += int64(len(out.data))
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	dir, err := writeNewContext(cfg, into, opts.Description, a.manifest.Scope, outputs)
+	if err != nil {
+		return nil, err
+	}
+	result.Dir = dir
+	return result, nil
+}
+
+// mergeFile merges a single path. It returns nil output when the file should
+// be absent from the result.
+func mergeFile(relPath string, a, b, base *mergeSide, resolve func(MergeConflict) string, result *MergeResult) (*pendingFile, []MergeConflict, error) {
+	ea, inA := a.entries[relPath]
+	eb, inB := b.entries[relPath]
+	eBase, inBase := base.entries[relPath]
+
+	take := func(side *mergeSide, e FileEntry) (*pendingFile, []MergeConflict, error) {
+		data, err := side.read(relPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read %s: %w", relPath, err)
+		}
+		return &pendingFile{entry: e, data: data}, nil, nil
+	}
+
+	changedA := inA != inBase || (inA && ea.Checksum != eBase.Checksum)
+	changedB := inB != inBase || (inB && eb.Checksum != eBase.Checksum)
+
+	switch {
+	case inA && inB && ea.Checksum == eb.Checksum:
+		return take(a, ea)
+	case !changedA:
+		if !inB {
+			return nil, nil, nil
+		}
+		return take(b, eb)
+	case !changedB:
+		if !inA {
+			return nil, nil, nil
+		}
+		return take(a, ea)
+	}
+
+	// Both sides changed the file.
+	if !inA || !inB {
+		c := MergeConflict{RelPath: relPath, InA: inA, InB: inB}
+		switch resolve(c) {
+		case MergeSideA:
+			result.Resolved = append(result.Resolved, c)
+			if !inA {
+				return nil, nil, nil
+			}
+			return take(a, ea)
+		case MergeSideB:
+			result.Resolved = append(result.Resolved, c)
+			if !inB {
+				return nil, nil, nil
+			}
+			return take(b, eb)
+		}
+		return nil, []MergeConflict{c}, nil
+	}
+
+	dataA, err := a.read(relPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", relPath, err)
+	}
+	dataB, err := b.read(relPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", relPath, err)
+	}
+	var dataBase []byte
+	if inBase {
+		if dataBase, err = base.read(relPath); err != nil {
+			return nil, nil, fmt.Errorf("read %s: %w", relPath, err)
+		}
+	}
+
+	if strings.HasSuffix(relPath, ".json") {
+		if objA, objB, objBase, ok := decodeJSONObjects(dataA, dataB, dataBase, inBase); ok {
+			var conflicts []MergeConflict
+			merged := mergeJSONObjects(relPath, "", objBase, objA, objB, resolve, result, &conflicts)
+			data, err := json.MarshalIndent(merged, "", "  ")
+			if err != nil {
+				return nil, nil, fmt.Errorf("marshal %s: %w", relPath, err)
+			}
+			data = append(data, '\n')
+			return &pendingFile{entry: ea, data: data}, conflicts, nil
+		}
+	}
+
+	c := MergeConflict{RelPath: relPath, InA: true, InB: true, A: string(dataA), B: string(dataB)}
+	switch resolve(c) {
+	case MergeSideA:
+		result.Resolved = append(result.Resolved, c)
+		return &pendingFile{entry: ea, data: dataA}, nil, nil
+	case MergeSideB:
+		result.Resolved = append(result.Resolved, c)
+		return &pendingFile{entry: eb, data: dataB}, nil, nil
+	}
+	result.Marked = append(result.Marked, c)
+	return &pendingFile{entry: ea, data: conflictMarkers(dataA, dataB)}, nil, nil
+}
+
+// decodeJSONObjects parses all sides as JSON objects. ok is false if any
+// present side is not a JSON object.
+func decodeJSONObjects(dataA, dataB, dataBase []byte, inBase bool) (objA, objB, objBase map[string]any, ok bool) {
+	if json.Unmarshal(dataA, &objA) != nil || objA == nil {
+		return nil, nil, nil, false
+	}
+	if json.Unmarshal(dataB, &objB) != nil || objB == nil {
+		return nil, nil, nil, false
+	}
+	if inBase {
+		if json.Unmarshal(dataBase, &objBase) != nil {
+			return nil, nil, nil, false
+		}
+	}
+	return objA, objB, objBase, true
+}
+
+// mergeJSONObjects performs a recursive three-way merge of JSON objects.
+// Nested objects are merged key by key; all other values are atomic.
+func mergeJSONObjects(relPath, prefix string, base, a, b map[string]any, resolve func(MergeConflict) string, result *MergeResult, conflicts *[]MergeConflict) map[string]any {
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	merged := map[string]any{}
+	for k := range keys {
+		va, inA := a[k]
+		vb, inB := b[k]
+		vBase, inBase := base[k]
+		keyPath := k
+		if prefix != "" {
+			keyPath = prefix + "." + k
+		}
+
+		if inA && inB && reflect.DeepEqual(va, vb) {
+			merged[k] = va
+			continue
+		}
+
+		subA, okA := va.(map[string]any)
+		subB, okB := vb.(map[string]any)
+		if okA && okB {
+			subBase, _ := vBase.(map[string]any)
+			merged[k] = mergeJSONObjects(relPath, keyPath, subBase, subA, subB, resolve, result, conflicts)
+			continue
+		}
+
+		changedA := inA != inBase || (inA && !reflect.DeepEqual(va, vBase))
+		changedB := inB != inBase || (inB && !reflect.DeepEqual(vb, vBase))
+
+		pick := ""
+		switch {
+		case !changedA:
+			pick = MergeSideB
+		case !changedB:
+			pick = MergeSideA
+		default:
+			c := MergeConflict{RelPath: relPath, Key: keyPath, InA: inA, InB: inB}
+			if inA {
+				c.A = renderJSONValue(va)
+			}
+			if inB {
+				c.B = renderJSONValue(vb)
+			}
+			pick = resolve(c)
+			if pick == "" {
+				*conflicts = append(*conflicts, c)
+				continue
+			}
+			result.Resolved = append(result.Resolved, c)
+		}
+
+		if pick == MergeSideA && inA {
+			merged[k] = va
+		} else if pick == MergeSideB && inB {
+			merged[k] = vb
+		}
+	}
+	return merged
+}
+
+func renderJSONValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// conflictMarkers wraps both versions of a file in git-style conflict markers.
+func conflictMarkers(a, b []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<<<<<<< a\n")
+	buf.Write(a)
+	if len(a) > 0 && a[len(a)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("=======\n")
+	buf.Write(b)
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(">>>>>>> b\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,254 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// saveProjectContext writes the given live files (relative to .claude/, or
+// "CLAUDE.md"/".mcp.json" for extra files) and saves them as a context.
+func saveProjectContext(t *testing.T, cfg *config.Config, name string, files map[string]string) {
+	t.Helper()
+	if err := ClearManagedFiles(cfg); err != nil {
+		t.Fatal(err)
+	}
+	for relPath, content := range files {
+		var path string
+		switch relPath {
+		case "CLAUDE.md":
+			path = cfg.Scope.ExtraFileByTag("claudemd").Path
+		case ".mcp.json":
+			path = cfg.Scope.ExtraFileByTag("mcpjson").Path
+		default:
+			path = filepath.Join(cfg.Scope.DotClaudeDir, relPath)
+		}
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	if _, err := Save(SaveOptions{Name: name, Config: cfg}); err != nil {
+		t.Fatalf("Save %s failed: %v", name, err)
+	}
+}
+
+func readMergedJSON(t *testing.T, cfg *config.Config, name, relPath string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(cfg.ContextsDir(), name, relPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		t.Fatalf("merged %s is not valid JSON: %v\n%s", relPath, err, data)
+	}
+	return obj
+}
+
+func TestMergeJSONKeysWithBase(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+
+	saveProjectContext(t, cfg, "base", map[string]string{
+		".mcp.json":     `{"mcpServers":{"jira":{"command":"jira"}}}`,
+		"settings.json": `{"model":"opus","theme":"dark"}`,
+	})
+	saveProjectContext(t, cfg, "ours", map[string]string{
+		".mcp.json":     `{"mcpServers":{"jira":{"command":"jira"},"github":{"command":"gh"}}}`,
+		"settings.json": `{"model":"sonnet","theme":"dark"}`,
+	})
+	saveProjectContext(t, cfg, "theirs", map[string]string{
+		".mcp.json":     `{"mcpServers":{"slack":{"command":"slack"}}}`,
+		"settings.json": `{"model":"opus","theme":"light"}`,
+	})
+
+	result, err := Merge(MergeOptions{A: "ours", B: "theirs", Base: "base", Into: "combined", Config: cfg})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(result.Marked) != 0 || len(result.Resolved) != 0 {
+		t.Errorf("expected clean merge, got %+v", result)
+	}
+
+	mcp := readMergedJSON(t, cfg, "combined", ".mcp.json")
+	servers := mcp["mcpServers"].(map[string]any)
+	if _, ok := servers["github"]; !ok {
+		t.Error("expected github server added by ours")
+	}
+	if _, ok := servers["slack"]; !ok {
+		t.Error("expected slack server added by theirs")
+	}
+	if _, ok := servers["jira"]; ok {
+		t.Error("expected jira server removed by theirs")
+	}
+
+	settings := readMergedJSON(t, cfg, "combined", "dotclaude/settings.json")
+	if settings["model"] != "sonnet" || settings["theme"] != "light" {
+		t.Errorf("unexpected merged settings: %v", settings)
+	}
+
+	m, err := ReadManifest(filepath.Join(cfg.ContextsDir(), "combined"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Scope != "project" || len(m.Files) != 2 {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+	if m.Checksum != ManifestChecksum(m.Files) {
+		t.Error("expected manifest checksum to match files")
+	}
+	assertContextDirMode(t, cfg, "combined")
+}
+
+func TestMergeDetectsCommonRevision(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+
+	original := map[string]string{"settings.json": `{"model":"opus","theme":"dark"}`}
+	saveProjectContext(t, cfg, "ours", original)
+	saveProjectContext(t, cfg, "theirs", original)
+
+	resave := func(name, settings string) {
+		t.Helper()
+		os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(settings), 0644)
+		if _, err := Save(SaveOptions{Name: name, Overwrite: true, KeepRevision: true, Config: cfg}); err != nil {
+			t.Fatalf("Save %s failed: %v", name, err)
+		}
+	}
+	resave("ours", `{"model":"sonnet","theme":"dark"}`)
+	resave("theirs", `{"model":"opus","theme":"light"}`)
+
+	result, err := Merge(MergeOptions{A: "ours", B: "theirs", Into: "combined", Config: cfg})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if !strings.HasPrefix(result.Base, "ours@") && !strings.HasPrefix(result.Base, "theirs@") {
+		t.Errorf("expected a revision as merge base, got %q", result.Base)
+	}
+	settings := readMergedJSON(t, cfg, "combined", "dotclaude/settings.json")
+	if settings["model"] != "sonnet" || settings["theme"] != "light" {
+		t.Errorf("unexpected merged settings: %v", settings)
+	}
+}
+
+func TestMergePicksLatestSharedRevision(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+
+	resave := func(name, settings string) {
+		t.Helper()
+		os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(settings), 0644)
+		if _, err := Save(SaveOptions{Name: name, Overwrite: true, KeepRevision: true, Config: cfg}); err != nil {
+			t.Fatalf("Save %s failed: %v", name, err)
+		}
+	}
+	first := map[string]string{"settings.json": `{"model":1,"theme":1}`}
+	saveProjectContext(t, cfg, "ours", first)
+	saveProjectContext(t, cfg, "theirs", first)
+	// Both contexts share the revisions holding the first and second
+	// snapshots, kept in a different order on each side.
+	resave("ours", `{"model":2,"theme":2}`)
+	resave("theirs", `{"model":2,"theme":2}`)
+	resave("theirs", `{"model":2,"theme":3}`)
+	resave("ours", `{"model":3,"theme":2}`)
+
+	var bases []string
+	for _, pair := range [][2]string{{"ours", "theirs"}, {"theirs", "ours"}} {
+		into := pair[0] + "-" + pair[1]
+		result, err := Merge(MergeOptions{A: pair[0], B: pair[1], Into: into, Config: cfg})
+		if err != nil {
+			t.Fatalf("Merge %v failed: %v", pair, err)
+		}
+		settings := readMergedJSON(t, cfg, into, "dotclaude/settings.json")
+		if settings["model"] != float64(3) || settings["theme"] != float64(3) {
+			t.Errorf("Merge %v with base %s: settings %v", pair, result.Base, settings)
+		}
+		bases = append(bases, result.Base)
+	}
+	if bases[0] != bases[1] {
+		t.Errorf("merge base depends on argument order: %v", bases)
+	}
+}
+
+func TestMergeJSONConflictResolved(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ours", map[string]string{"settings.json": `{"model":"sonnet"}`})
+	saveProjectContext(t, cfg, "theirs", map[string]string{"settings.json": `{"model":"haiku"}`})
+
+	var seen []MergeConflict
+	_, err := Merge(MergeOptions{
+		A: "ours", B: "theirs", Into: "combined", Config: cfg,
+		Resolve: func(c MergeConflict) string {
+			seen = append(seen, c)
+			return MergeSideB
+		},
+	})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(seen) != 1 || seen[0].Key != "model" || seen[0].A != `"sonnet"` || seen[0].B != `"haiku"` {
+		t.Errorf("unexpected conflicts: %+v", seen)
+	}
+	settings := readMergedJSON(t, cfg, "combined", "dotclaude/settings.json")
+	if settings["model"] != "haiku" {
+		t.Errorf("expected theirs to win, got %v", settings["model"])
+	}
+}
+
+func TestMergeJSONConflictUnresolved(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ours", map[string]string{"settings.json": `{"model":"sonnet"}`})
+	saveProjectContext(t, cfg, "theirs", map[string]string{"settings.json": `{"model":"haiku"}`})
+
+	_, err := Merge(MergeOptions{A: "ours", B: "theirs", Into: "combined", Config: cfg})
+	var conflictErr *UnresolvedConflictsError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected UnresolvedConflictsError, got %v", err)
+	}
+	if len(conflictErr.Conflicts) != 1 || conflictErr.Conflicts[0].Key != "model" {
+		t.Errorf("unexpected conflicts: %+v", conflictErr.Conflicts)
+	}
+	if ContextExists(cfg.ContextsDir(), "combined") {
+		t.Error("expected no context to be created on unresolved conflicts")
+	}
+}
+
+func TestMergeTextConflictMarkers(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ours", map[string]string{"CLAUDE.md": "# Ours\n", "rules/a.md": "a"})
+	saveProjectContext(t, cfg, "theirs", map[string]string{"CLAUDE.md": "# Theirs\n", "rules/b.md": "b"})
+
+	result, err := Merge(MergeOptions{A: "ours", B: "theirs", Into: "combined", Config: cfg})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(result.Marked) != 1 || result.Marked[0].RelPath != "CLAUDE.md" {
+		t.Errorf("expected CLAUDE.md conflict markers, got %+v", result.Marked)
+	}
+	if result.Files != 3 {
+		t.Errorf("expected 3 files (CLAUDE.md + both rules), got %d", result.Files)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(cfg.ContextsDir(), "combined", "CLAUDE.md"))
+	want := "<<<<<<< a\n# Ours\n=======\n# Theirs\n>>>>>>> b\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	current, _ := GetCurrent(cfg)
+	if current == "combined" {
+		t.Error("expected merge not to switch to the merged context")
+	}
+}
+
+func TestMergeRejectsExistingTarget(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ours", map[string]string{"CLAUDE.md": "x"})
+	saveProjectContext(t, cfg, "theirs", map[string]string{"CLAUDE.md": "x"})
+
+	_, err := Merge(MergeOptions{A: "ours", B: "theirs", Into: "ours", Config: cfg})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+}
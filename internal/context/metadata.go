@@ -0,0 +1,93 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// metaTokenRe matches valid tags and label keys.
+var metaTokenRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+// MetadataEdit describes changes to a context's description, tags and labels.
+// Removals are applied before additions.
+type MetadataEdit struct {
+	Description  *string // nil leaves the description unchanged
+	AddTags      []string
+	RemoveTags   []string
+	SetLabels    map[string]string
+	RemoveLabels []string
+}
+
+// Validate checks tag names and label keys.
+func (e MetadataEdit) Validate() error {
+	for _, tag := range slices.Concat(e.AddTags, e.RemoveTags) {
+		if !metaTokenRe.MatchString(tag) {
+			return fmt.Errorf("%w: invalid tag %q", ErrInvalidName, tag)
+		}
+	}
+	for key := range e.SetLabels {
+		if !metaTokenRe.MatchString(key) {
+			return fmt.Errorf("%w: invalid label key %q", ErrInvalidName, key)
+		}
+	}
+	for _, key := range e.RemoveLabels {
+		if !metaTokenRe.MatchString(key) {
+			return fmt.Errorf("%w: invalid label key %q", ErrInvalidName, key)
+		}
+	}
+	return nil
+}
+
+// EditMetadata applies edit to the manifest of a saved context. Like
+// SetLocked, it leaves the snapshot and its UpdatedAt time alone, so locked
+// contexts can be edited too.
+func EditMetadata(cfg *config.Config, slug string, edit MetadataEdit) (*Manifest, error) {
+	if err := edit.Validate(); err != nil {
+		return nil, err
+	}
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	m, err := ReadManifest(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
+	}
+
+	if edit.Description != nil {
+		m.Description = *edit.Description
+	}
+	m.Tags = slices.DeleteFunc(m.Tags, func(tag string) bool { return slices.Contains(edit.RemoveTags, tag) })
+	for _, tag := range edit.AddTags {
+		if !slices.Contains(m.Tags, tag) {
+			m.Tags = append(m.Tags, tag)
+		}
+	}
+	slices.Sort(m.Tags)
+	if len(m.Tags) == 0 {
+		m.Tags = nil
+	}
+	for _, key := range edit.RemoveLabels {
+		delete(m.Labels, key)
+	}
+	for key, value := range edit.SetLabels {
+		if m.Labels == nil {
+			m.Labels = map[string]string{}
+		}
+		m.Labels[key] = value
+	}
+	if len(m.Labels) == 0 {
+		m.Labels = nil
+	}
+
+	if err := WriteManifest(contextDir, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// HasTag reports whether the context is tagged with tag.
+func (m *Manifest) HasTag(tag string) bool {
+	return slices.Contains(m.Tags, tag)
+}
@@ -0,0 +1,62 @@
+package context
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+func TestEditMetadata(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Project"), 0644)
+	cfg, err := config.LoadWithScope("", config.ProjectScopeAt(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Save(SaveOptions{Name: "work", Description: "Work", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+
+	desc := "Acme work"
+	m, err := EditMetadata(cfg, "work", MetadataEdit{
+		Description: &desc,
+		AddTags:     []string{"prod", "client-a", "prod"},
+		SetLabels:   map[string]string{"account": "acme", "env": "prod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Description != desc || !slices.Equal(m.Tags, []string{"client-a", "prod"}) || m.Labels["account"] != "acme" {
+		t.Errorf("after edit: %+v", m)
+	}
+
+	m, err = EditMetadata(cfg, "work", MetadataEdit{RemoveTags: []string{"prod"}, RemoveLabels: []string{"env"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Description != desc || !slices.Equal(m.Tags, []string{"client-a"}) || len(m.Labels) != 1 {
+		t.Errorf("after removals: %+v", m)
+	}
+
+	// Overwriting the snapshot (auto-save) keeps the metadata.
+	created := m.CreatedAt
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Changed"), 0644)
+	if _, err := Save(SaveOptions{Name: "work", Overwrite: true, Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = ReadManifest(filepath.Join(cfg.ContextsDir(), "work"))
+	if m.Description != desc || !m.HasTag("client-a") || m.Labels["account"] != "acme" || !m.CreatedAt.Equal(created) {
+		t.Errorf("metadata lost on overwrite: %+v", m)
+	}
+
+	if _, err := EditMetadata(cfg, "work", MetadataEdit{AddTags: []string{"two words"}}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("invalid tag: err = %v, want ErrInvalidName", err)
+	}
+	if _, err := EditMetadata(cfg, "missing", MetadataEdit{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing context: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,193 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
+)
+
+// ProfileStep switches one scope to a context as part of a profile.
+type ProfileStep struct {
+	Config *config.Config
+	Name   string
+}
+
+// ProfileOptions configures ApplyProfile.
+type ProfileOptions struct {
+	Profile    string
+	Steps      []ProfileStep
+	BackupsDir string // the combined backup is created here
+	DryRun     bool
+	Force      bool
+	Verbose    bool
+	Tracked    string // see RestoreOptions.Tracked
+}
+
+// ProfileStepResult is the outcome of one step of a profile.
+type ProfileStepResult struct {
+	*RestoreResult
+	Scope         string `json:"scope"`
+	Root          string `json:"root,omitempty"`
+	Previous      string `json:"previous,omitempty"`
+	AlreadyActive bool   `json:"alreadyActive,omitempty"`
+}
+
+// ProfileResult holds the result of ApplyProfile.
+type ProfileResult struct {
+	Profile   string              `json:"profile"`
+	Steps     []ProfileStepResult `json:"steps"`
+	BackupDir string              `json:"backupDir,omitempty"`
+}
+
+// ApplyProfile switches every step's scope to its context. All steps are
+// checked first (context exists, scope, validation, git safety), then the
+// live files of all scopes are copied into one combined backup. If a switch
+// fails, the scopes switched so far, including the failing one, are restored
+// from that backup together with their active markers.
+//
+// Steps whose context is already active are skipped unless opts.Force is set.
+func ApplyProfile(opts ProfileOptions) (*ProfileResult, error) {
+	result := &ProfileResult{Profile: opts.Profile, Steps: []ProfileStepResult{}}
+	var pending []int
+	for i, step := range opts.Steps {
+		cfg := step.Config
+		slug := Slugify(step.Name)
+		if !ContextExists(cfg.ContextsDir(), slug) {
+			return nil, fmt.Errorf("%s: context %q %w", stepLabel(cfg), slug, ErrNotFound)
+		}
+		previous, _ := GetCurrent(cfg)
+		sr := ProfileStepResult{Scope: string(cfg.Scope.Type), Root: cfg.Scope.Root, Previous: previous}
+		if previous == slug && !opts.Force {
+			sr.RestoreResult = &RestoreResult{Name: slug}
+			sr.AlreadyActive = true
+		} else {
+			check, err := Restore(RestoreOptions{Name: slug, DryRun: true, Force: opts.Force, Config: cfg, Tracked: opts.Tracked})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", stepLabel(cfg), err)
+			}
+			sr.RestoreResult = check
+			pending = append(pending, i)
+		}
+		result.Steps = append(result.Steps, sr)
+	}
+	if opts.DryRun || len(pending) == 0 {
+		return result, nil
+	}
+
+	name := fmt.Sprintf("profile-%s-%s", opts.Profile, time.Now().Format("20060102-150405"))
+	result.BackupDir = filepath.Join(opts.BackupsDir, name)
+	for _, i := range pending {
+		if err := backupStep(stepBackupDir(result.BackupDir, i), opts.Steps[i].Config); err != nil {
+			return nil, fmt.Errorf("backup failed: %w", err)
+		}
+	}
+
+	for n, i := range pending {
+		step := opts.Steps[i]
+		restored, err := Restore(RestoreOptions{
+			Name:     step.Name,
+			Force:    opts.Force,
+			Verbose:  opts.Verbose,
+			Config:   step.Config,
+			Tracked:  opts.Tracked,
+			NoBackup: true,
+		})
+		if err != nil {
+			err = fmt.Errorf("%s: %w", stepLabel(step.Config), err)
+			var rollbackErrs []error
+			for _, j := range pending[:n+1] {
+				if rerr := rollbackStep(stepBackupDir(result.BackupDir, j), opts.Steps[j].Config); rerr != nil {
+					rollbackErrs = append(rollbackErrs, fmt.Errorf("%s: %w", stepLabel(opts.Steps[j].Config), rerr))
+				}
+			}
+			if len(rollbackErrs) > 0 {
+				return nil, fmt.Errorf("%w; rollback incomplete, backup kept in %s: %w", err, result.BackupDir, errors.Join(rollbackErrs...))
+			}
+			return nil, fmt.Errorf("%w; all scopes were rolled back", err)
+		}
+		result.Steps[i].RestoreResult = restored
+	}
+	return result, nil
+}
+
+// stepLabel names the scope of a step in messages.
+func stepLabel(cfg *config.Config) string {
+	if cfg.Scope.Root != "" {
+		return fmt.Sprintf("%s scope %s", cfg.Scope.Type, cfg.Scope.Root)
+	}
+	return fmt.Sprintf("%s scope", cfg.Scope.Type)
+}
+
+func stepBackupDir(backupDir string, i int) string {
+	return filepath.Join(backupDir, strconv.Itoa(i))
+}
+
+// backupStep copies the live files of a step's scope into dir and records
+// its active context in dir/current.
+func backupStep(dir string, cfg *config.Config) error {
+	if err := backupLiveFiles(dir, cfg.Scope); err != nil {
+		return err
+	}
+	current, err := GetCurrent(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "current"), []byte(current), 0644)
+}
+
+// rollbackStep puts back the live files and active context saved by backupStep.
+func rollbackStep(dir string, cfg *config.Config) error {
+	scope := cfg.Scope
+	marker := restoringMarker(cfg)
+	if err := os.WriteFile(marker, nil, 0644); err == nil {
+		defer os.Remove(marker)
+	}
+
+	if err := clearManagedFiles(scope, nil); err != nil {
+		return err
+	}
+	for _, ef := range scope.ExtraFiles {
+		src := filepath.Join(dir, filepath.Base(ef.Path))
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := fileutil.CopyFile(src, ef.Path); err != nil {
+			return err
+		}
+	}
+	dotclaude := filepath.Join(dir, "dotclaude")
+	err := filepath.Walk(dotclaude, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dotclaude {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(dotclaude, path)
+		return fileutil.CopyFile(path, filepath.Join(scope.DotClaudeDir, rel))
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "current"))
+	if err != nil {
+		return err
+	}
+	if previous := string(data); previous != "" {
+		return SetCurrent(cfg, previous)
+	}
+	if err := os.Remove(cfg.CurrentFile()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// profileProject creates a project with contexts "one" and "two" whose
+// CLAUDE.md reads "<prefix>1" and "<prefix>2"; "two" is active.
+func profileProject(t *testing.T, prefix string) (*config.Config, string) {
+	t.Helper()
+	root := t.TempDir()
+	cfg, err := config.LoadWithScope("", config.ProjectScopeAt(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claudeMD := filepath.Join(root, "CLAUDE.md")
+	for i, name := range []string{"one", "two"} {
+		os.WriteFile(claudeMD, []byte(fmt.Sprintf("%s%d", prefix, i+1)), 0644)
+		if _, err := Save(SaveOptions{Name: name, Config: cfg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cfg, claudeMD
+}
+
+func readString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestApplyProfile(t *testing.T) {
+	cfgA, mdA := profileProject(t, "a")
+	cfgB, mdB := profileProject(t, "b")
+	backups := t.TempDir()
+
+	result, err := ApplyProfile(ProfileOptions{
+		Profile:    "client",
+		Steps:      []ProfileStep{{cfgA, "one"}, {cfgB, "one"}},
+		BackupsDir: backups,
+	})
+	if err != nil {
+		t.Fatalf("ApplyProfile: %v", err)
+	}
+	if got := readString(t, mdA) + readString(t, mdB); got != "a1b1" {
+		t.Errorf("live files = %q, want a1b1", got)
+	}
+	for _, cfg := range []*config.Config{cfgA, cfgB} {
+		if current, _ := GetCurrent(cfg); current != "one" {
+			t.Errorf("current of %s = %q, want one", cfg.Scope.Root, current)
+		}
+	}
+	if len(result.Steps) != 2 || result.Steps[0].Previous != "two" || result.Steps[1].Root != cfgB.Scope.Root {
+		t.Errorf("unexpected steps: %+v", result.Steps)
+	}
+	if !strings.HasPrefix(filepath.Base(result.BackupDir), "profile-client-") {
+		t.Errorf("BackupDir = %s", result.BackupDir)
+	}
+	if got := readString(t, filepath.Join(result.BackupDir, "1", "CLAUDE.md")); got != "b2" {
+		t.Errorf("combined backup of b = %q, want b2", got)
+	}
+
+	// Applying again is a no-op.
+	result, err = ApplyProfile(ProfileOptions{Profile: "client", Steps: []ProfileStep{{cfgA, "one"}}, BackupsDir: backups})
+	if err != nil || !result.Steps[0].AlreadyActive || result.BackupDir != "" {
+		t.Errorf("re-apply = %+v, %v; want already active without backup", result, err)
+	}
+}
+
+func TestApplyProfileRollback(t *testing.T) {
+	cfgA, mdA := profileProject(t, "a")
+	cfgB, mdB := profileProject(t, "b")
+	os.WriteFile(mdA, []byte("a-live"), 0644)
+	os.Remove(cfgB.CurrentFile())
+	cfgB.Hooks.PreSwitch = config.HookCommands{"exit 1"}
+
+	_, err := ApplyProfile(ProfileOptions{
+		Profile:    "client",
+		Steps:      []ProfileStep{{cfgA, "one"}, {cfgB, "one"}},
+		BackupsDir: t.TempDir(),
+	})
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected a rolled back failure, got %v", err)
+	}
+	if got := readString(t, mdA); got != "a-live" {
+		t.Errorf("project a live file = %q, want a-live restored", got)
+	}
+	if current, _ := GetCurrent(cfgA); current != "two" {
+		t.Errorf("current of a = %q, want two restored", current)
+	}
+	if got := readString(t, mdB); got != "b2" {
+		t.Errorf("project b live file = %q, want b2 untouched", got)
+	}
+	if _, err := os.Stat(cfgB.CurrentFile()); !os.IsNotExist(err) {
+		t.Errorf("project b got a current marker: %v", err)
+	}
+}
+
+func TestApplyProfileChecksFirst(t *testing.T) {
+	cfgA, mdA := profileProject(t, "a")
+	cfgB, _ := profileProject(t, "b")
+
+	_, err := ApplyProfile(ProfileOptions{
+		Profile:    "client",
+		Steps:      []ProfileStep{{cfgA, "one"}, {cfgB, "missing"}},
+		BackupsDir: t.TempDir(),
+	})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if got := readString(t, mdA); got != "a2" {
+		t.Errorf("project a was switched before the profile was checked: %q", got)
+	}
+}
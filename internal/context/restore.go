@@ -18,13 +18,27 @@ type RestoreOptions struct {
 	Force   bool
 	Verbose bool
 	Config  *config.Config
+
+	// Tracked overrides cfg.Git's handling of git-tracked files when set
+	// (one of the config.GitTracked* modes).
+	Tracked string
+
+	// NoBackup skips the pre-switch backup, for callers that back up the
+	// live files themselves (profiles).
+	NoBackup bool
 }
 
 // RestoreResult holds the result of a restore operation.
 type RestoreResult struct {
-	Name          string
-	FilesRestored int
-	BackupDir     string
+	Name          string `json:"name"`
+	FilesRestored int    `json:"filesRestored"`
+	BackupDir     string `json:"backupDir,omitempty"`
+
+	// Git-tracked files (relative to the repository root) that had
+	// uncommitted changes, were left alone, or were marked skip-worktree.
+	TrackedModified []string `json:"trackedModified,omitempty"`
+	SkippedTracked  []string `json:"skippedTracked,omitempty"`
+	SkipWorktree    []string `json:"skipWorktree,omitempty"`
 }
 
 // Restore applies a saved context to the current Claude Code state.
@@ -38,91 +52,131 @@ func Restore(opts RestoreOptions) (*RestoreResult, error) {
 	contextDir := filepath.Join(cfg.ContextsDir(), slug)
 	manifest, err := ReadManifest(contextDir)
 	if err != nil {
-		return nil, fmt.Errorf("context %q not found: %w", slug, err)
+		return nil, fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
 	}
 
 	// Validate manifest scope matches current scope
 	if manifest.Scope != "" && manifest.Scope != string(scope.Type) {
-		return nil, fmt.Errorf("context %q was saved with %s scope, but current scope is %s", slug, manifest.Scope, scope.Type)
+		return nil, fmt.Errorf("%w: context %q was saved with %s scope, but current scope is %s", ErrScopeMismatch, slug, manifest.Scope, scope.Type)
+	}
+
+	if cfg.Validation.Action() != config.ValidateOff {
+		issues, err := Lint(cfg, slug)
+		if err != nil {
+			return nil, err
+		}
+		if err := enforceValidation(cfg, issues, opts.Force); err != nil {
+			return nil, fmt.Errorf("context %q: %w (use --force to restore anyway)", slug, err)
+		}
+	}
+
+	guard, err := newGitGuard(cfg, opts.Tracked, manifest)
+	if err != nil {
+		return nil, err
+	}
+	var modified []string
+	if guard != nil {
+		if modified, err = guard.checkModified(cfg, slug, opts.Force); err != nil {
+			return nil, err
+		}
 	}
 
 	if opts.DryRun {
 		return &RestoreResult{
-			Name:          slug,
-			FilesRestored: len(manifest.Files),
+			Name:            slug,
+			FilesRestored:   len(manifest.Files) - guard.keptRestores(),
+			TrackedModified: modified,
+			SkippedTracked:  guard.skipped(),
 		}, nil
 	}
 
+	if err := scope.CheckWritable(); err != nil {
+		return nil, err
+	}
+
+	previous, _ := GetCurrent(cfg)
+	hookEnv := HookEnv{Context: slug, From: previous, To: slug}
+	if err := runHooks(cfg, HookPreSwitch, hookEnv); err != nil {
+		return nil, err
+	}
+
 	// 1. Auto-save current context before switching
 	AutoSaveCurrent(cfg, slug)
 
 	// 2. Create backup of current state before switching
-	backupDir, err := createBackup(cfg, scope)
-	if err != nil && !opts.Force {
-		return nil, fmt.Errorf("backup failed: %w (use --force to skip)", err)
+	var backupDir string
+	if !opts.NoBackup {
+		backupDir, err = createBackup(cfg, scope)
+		if err != nil && !opts.Force {
+			return nil, fmt.Errorf("backup failed: %w (use --force to skip)", err)
+		}
+	}
+
+	// Mark the writes below as ours so watchers don't capture them.
+	marker := restoringMarker(cfg)
+	if err := os.WriteFile(marker, nil, 0644); err == nil {
+		defer os.Remove(marker)
 	}
 
 	// 3. Clear managed files so stale files from previous context don't linger
-	if err := ClearManagedFiles(cfg); err != nil {
+	if err := clearManagedFiles(scope, guard.keep); err != nil {
 		return nil, fmt.Errorf("clear before restore: %w", err)
 	}
 
 	// 4. Restore files (copy from snapshot to live paths)
-	restored, err := restoreCopy(contextDir, scope, manifest)
+	restored, err := restoreCopy(contextDir, scope, manifest, guard.keep)
 	if err != nil {
 		return nil, err
 	}
+	skipWorktree := guard.markSkipWorktree()
 
 	// 5. Update current marker
 	if err := SetCurrent(cfg, slug); err != nil {
 		return nil, err
 	}
 
+	if err := runHooks(cfg, HookPostSwitch, hookEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	return &RestoreResult{
-		Name:          slug,
-		FilesRestored: restored,
-		BackupDir:     backupDir,
+		Name:            slug,
+		FilesRestored:   restored,
+		BackupDir:       backupDir,
+		TrackedModified: modified,
+		SkippedTracked:  guard.skipped(),
+		SkipWorktree:    skipWorktree,
 	}, nil
 }
 
-// AutoSaveCurrent saves the current live state back to the active context's
-// snapshot before switching away. targetSlug is excluded to avoid saving over
-// the context we're about to switch to. A warning is printed to stderr if the
-// save fails, but the switch is not aborted.
-func AutoSaveCurrent(cfg *config.Config, targetSlug string) {
-	current, err := GetCurrent(cfg)
-	if err != nil || current == "" || current == targetSlug {
-		return
-	}
-	if !ContextExists(cfg.ContextsDir(), current) {
-		return
-	}
-	// Save live state back to current context (overwrite)
-	if _, err := Save(SaveOptions{
-		Name:      current,
-		Overwrite: true,
-		Config:    cfg,
-	}); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: auto-save of context %q failed: %v\n", current, err)
-	}
+// restoringMarkerMaxAge bounds how long a restore marker is honored, so one
+// left behind by a crash doesn't silence watchers forever.
+const restoringMarkerMaxAge = time.Minute
+
+func restoringMarker(cfg *config.Config) string {
+	return filepath.Join(cfg.StateDir(), ".restoring")
+}
+
+// Restoring reports whether a Restore is currently rewriting the live files
+// of cfg's scope.
+func Restoring(cfg *config.Config) bool {
+	info, err := os.Stat(restoringMarker(cfg))
+	return err == nil && time.Since(info.ModTime()) < restoringMarkerMaxAge
 }
 
 // restoreCopy copies files from snapshot to live paths (additive overlay).
-func restoreCopy(contextDir string, scope *config.Scope, manifest *Manifest) (int, error) {
+// Live paths for which keep returns true are left untouched.
+func restoreCopy(contextDir string, scope *config.Scope, manifest *Manifest, keep func(string) bool) (int, error) {
 	restored := 0
 	for _, entry := range manifest.Files {
 		srcPath := filepath.Join(contextDir, filepath.FromSlash(entry.RelPath))
 
-		var dstPath string
-		if isExtraFileSource(entry.Source) {
-			ef := scope.ExtraFileByTag(entry.Source)
-			if ef == nil {
-				continue // tag not recognized in current scope, skip gracefully
-			}
-			dstPath = ef.Path
-		} else {
-			relToDotClaude := strings.TrimPrefix(entry.RelPath, "dotclaude/")
-			dstPath = filepath.Join(scope.DotClaudeDir, filepath.FromSlash(relToDotClaude))
+		dstPath, ok := livePath(scope, entry)
+		if !ok {
+			continue // tag not recognized in current scope, skip gracefully
+		}
+		if keep != nil && keep(dstPath) {
+			continue
 		}
 
 		if err := fileutil.CopyFile(srcPath, dstPath); err != nil {
@@ -133,14 +187,40 @@ func restoreCopy(contextDir string, scope *config.Scope, manifest *Manifest) (in
 	return restored, nil
 }
 
+// livePath returns the live location of a snapshot entry in the given scope.
+// Returns false if the entry's source tag is not recognized by the scope, or
+// if the file belongs to another scope (e.g. settings.local.json in a
+// project context saved before the local scope existed).
+func livePath(scope *config.Scope, entry FileEntry) (string, bool) {
+	if isExtraFileSource(entry.Source) {
+		ef := scope.ExtraFileByTag(entry.Source)
+		if ef == nil {
+			return "", false
+		}
+		return ef.Path, true
+	}
+	relToDotClaude := strings.TrimPrefix(entry.RelPath, "dotclaude/")
+	if scope.Reserved(relToDotClaude) {
+		return "", false
+	}
+	return filepath.Join(scope.DotClaudeDir, filepath.FromSlash(relToDotClaude)), true
+}
+
 // ClearManagedFiles removes all managed files for the current scope.
 // This includes the extra file (CLAUDE.md or claude.json) and matched files
 // inside the .claude/ directory. Used by --from-scratch to start clean.
 func ClearManagedFiles(cfg *config.Config) error {
-	scope := cfg.Scope
+	return clearManagedFiles(cfg.Scope, nil)
+}
 
+// clearManagedFiles is ClearManagedFiles, leaving alone the files for which
+// keep returns true.
+func clearManagedFiles(scope *config.Scope, keep func(string) bool) error {
 	// Remove extra files (CLAUDE.md/.mcp.json for project, claude.json for user)
 	for _, ef := range scope.ExtraFiles {
+		if keep != nil && keep(ef.Path) {
+			continue
+		}
 		if err := os.Remove(ef.Path); err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("remove %s: %w", filepath.Base(ef.Path), err)
 		}
@@ -157,6 +237,9 @@ func ClearManagedFiles(cfg *config.Config) error {
 			return fmt.Errorf("walk %s: %w", scope.DotClaudeDir, err)
 		}
 		for _, w := range walked {
+			if keep != nil && keep(w.AbsPath) {
+				continue
+			}
 			if err := os.Remove(w.AbsPath); err != nil && !os.IsNotExist(err) {
 				return fmt.Errorf("remove %s: %w", w.RelPath, err)
 			}
@@ -190,9 +273,14 @@ func removeEmptyDirs(root string) {
 func createBackup(cfg *config.Config, scope *config.Scope) (string, error) {
 	backupName := fmt.Sprintf("pre-switch-%s", time.Now().Format("20060102-150405"))
 	backupDir := filepath.Join(cfg.BackupsDir(), backupName)
+	return backupDir, backupLiveFiles(backupDir, scope)
+}
 
+// backupLiveFiles copies the managed live files of scope into backupDir,
+// extra files under their base name and .claude files under dotclaude/.
+func backupLiveFiles(backupDir string, scope *config.Scope) error {
 	if err := os.MkdirAll(backupDir, 0755); err != nil {
-		return "", err
+		return err
 	}
 
 	// Backup extra files (claude.json, CLAUDE.md, .mcp.json, etc.)
@@ -200,7 +288,7 @@ func createBackup(cfg *config.Config, scope *config.Scope) (string, error) {
 		if _, err := os.Stat(ef.Path); err == nil {
 			storedName := filepath.Base(ef.Path)
 			if err := fileutil.CopyFile(ef.Path, filepath.Join(backupDir, storedName)); err != nil {
-				return backupDir, err
+				return err
 			}
 		}
 	}
@@ -213,15 +301,15 @@ func createBackup(cfg *config.Config, scope *config.Scope) (string, error) {
 			scope.ExcludePatterns,
 		)
 		if err != nil {
-			return backupDir, err
+			return err
 		}
 		for _, w := range walked {
 			dst := filepath.Join(backupDir, "dotclaude", w.RelPath)
 			if err := fileutil.CopyFile(w.AbsPath, dst); err != nil {
-				return backupDir, err
+				return err
 			}
 		}
 	}
 
-	return backupDir, nil
+	return nil
 }
@@ -0,0 +1,127 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
+)
+
+// revisionTimeFormat names revision directories so they sort chronologically.
+const revisionTimeFormat = "20060102-150405.000"
+
+// Revision is a previous snapshot of a context kept before it was overwritten.
+type Revision struct {
+	ID       string
+	Dir      string
+	Manifest *Manifest
+}
+
+// revisionsDirFor returns the directory holding revisions of one context.
+func revisionsDirFor(cfg *config.Config, slug string) string {
+	return filepath.Join(cfg.RevisionsDir(), slug)
+}
+
+// keepRevision copies the current snapshot of a context into its revisions
+// directory and prunes revisions beyond the configured limit.
+func keepRevision(cfg *config.Config, slug string) (string, error) {
+	src := filepath.Join(cfg.ContextsDir(), slug)
+	if !ContextExists(cfg.ContextsDir(), slug) {
+		return "", nil
+	}
+	id := time.Now().Format(revisionTimeFormat)
+	dst := filepath.Join(revisionsDirFor(cfg, slug), id)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dst); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", time.Now().Format(revisionTimeFormat), i)
+		dst = filepath.Join(revisionsDirFor(cfg, slug), id)
+	}
+	if err := fileutil.CopyDir(src, dst); err != nil {
+		return "", fmt.Errorf("keep revision of %q: %w", slug, err)
+	}
+	pruneRevisions(cfg, slug, cfg.AutoSave.RevisionLimit())
+	return id, nil
+}
+
+// pruneRevisions removes the oldest revisions so at most limit remain.
+func pruneRevisions(cfg *config.Config, slug string, limit int) {
+	revs, err := ListRevisions(cfg, slug)
+	if err != nil || len(revs) <= limit {
+		return
+	}
+	for _, r := range revs[limit:] {
+		os.RemoveAll(r.Dir)
+	}
+}
+
+// ListRevisions returns the kept revisions of a context, newest first.
+func ListRevisions(cfg *config.Config, slug string) ([]Revision, error) {
+	dir := revisionsDirFor(cfg, slug)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var revs []Revision
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		revDir := filepath.Join(dir, e.Name())
+		m, err := ReadManifest(revDir)
+		if err != nil {
+			continue
+		}
+		revs = append(revs, Revision{ID: e.Name(), Dir: revDir, Manifest: m})
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].ID > revs[j].ID })
+	return revs, nil
+}
+
+// RestoreRevision replaces a context's snapshot with one of its revisions.
+// The snapshot being replaced is itself kept as a new revision. Live files
+// are not touched; switch to the context afterwards to apply it.
+func RestoreRevision(cfg *config.Config, slug, id string) error {
+	revDir := filepath.Join(revisionsDirFor(cfg, slug), id)
+	if _, err := ReadManifest(revDir); err != nil {
+		return fmt.Errorf("revision %q of context %q %w", id, slug, ErrNotFound)
+	}
+	if !ContextExists(cfg.ContextsDir(), slug) {
+		return fmt.Errorf("context %q %w", slug, ErrNotFound)
+	}
+	if IsLocked(cfg, slug) {
+		return lockedError(slug)
+	}
+
+	tmpDir, err := os.MkdirTemp(cfg.ContextsDir(), ".revision-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	os.Chmod(tmpDir, 0755)
+	if err := fileutil.CopyDir(revDir, tmpDir); err != nil {
+		return fmt.Errorf("copy revision: %w", err)
+	}
+
+	if _, err := keepRevision(cfg, slug); err != nil {
+		return err
+	}
+
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	if err := os.RemoveAll(contextDir); err != nil {
+		return fmt.Errorf("remove context: %w", err)
+	}
+	if err := os.Rename(tmpDir, contextDir); err != nil {
+		return fmt.Errorf("rename revision into place: %w", err)
+	}
+	return nil
+}
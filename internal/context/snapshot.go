@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pfldy2850/claudectx/internal/config"
@@ -16,27 +17,28 @@ func toSlash(p string) string { return filepath.ToSlash(p) }
 
 // SaveOptions configures the save operation.
 type SaveOptions struct {
-	Name        string
-	Description string
-	Overwrite   bool
-	DryRun      bool
-	Verbose     bool
-	Config      *config.Config
+	Name         string
+	Description  string
+	Overwrite    bool
+	KeepRevision bool // keep the overwritten snapshot as a revision
+	DryRun       bool
+	Verbose      bool
+	Config       *config.Config
 }
 
 // SaveResult holds the result of a save operation.
 type SaveResult struct {
-	Name      string
-	Dir       string
-	Files     int
-	TotalSize int64
+	Name      string `json:"name"`
+	Dir       string `json:"dir,omitempty"`
+	Files     int    `json:"files"`
+	TotalSize int64  `json:"totalSize"`
 }
 
 // Save creates a snapshot of the current Claude Code state.
 func Save(opts SaveOptions) (*SaveResult, error) {
 	slug := Slugify(opts.Name)
 	if slug == "" {
-		return nil, fmt.Errorf("invalid context name: %q", opts.Name)
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, opts.Name)
 	}
 
 	cfg := opts.Config
@@ -44,17 +46,45 @@ func Save(opts SaveOptions) (*SaveResult, error) {
 	contextDir := filepath.Join(cfg.ContextsDir(), slug)
 
 	if ContextExists(cfg.ContextsDir(), slug) && !opts.Overwrite {
-		return nil, fmt.Errorf("context %q already exists", slug)
+		return nil, fmt.Errorf("context %q %w", slug, ErrExists)
+	}
+	if opts.Overwrite && IsLocked(cfg, slug) {
+		return nil, lockedError(slug)
 	}
-
-	dotClaudeDir := scope.DotClaudeDir
 
 	if opts.DryRun {
-		return dryRunSave(slug, dotClaudeDir, cfg, opts)
+		return dryRunSave(slug, cfg)
+	}
+	if err := scope.CheckWritable(); err != nil {
+		return nil, err
 	}
 
-	// Clear existing context if overwriting
+	previous, _ := GetCurrent(cfg)
+	hookEnv := HookEnv{Context: slug, From: previous, To: slug}
+	if err := runHooks(cfg, HookPreSave, hookEnv); err != nil {
+		return nil, err
+	}
+
+	if cfg.Validation.Action() != config.ValidateOff {
+		issues, err := validateLive(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := enforceValidation(cfg, issues, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Overwrite && opts.KeepRevision {
+		if _, err := keepRevision(cfg, slug); err != nil {
+			return nil, err
+		}
+	}
+
+	// Clear existing context if overwriting, keeping its metadata
+	var previousManifest *Manifest
 	if opts.Overwrite {
+		previousManifest, _ = ReadManifest(contextDir)
 		os.RemoveAll(contextDir)
 	}
 
@@ -62,66 +92,36 @@ func Save(opts SaveOptions) (*SaveResult, error) {
 		return nil, fmt.Errorf("create context dir: %w", err)
 	}
 
+	live, err := collectLiveFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	var files []FileEntry
 	var totalSize int64
 
-	// 1. Snapshot extra files (claude.json for user scope; CLAUDE.md, .mcp.json for project scope)
-	for _, ef := range scope.ExtraFiles {
-		info, err := os.Stat(ef.Path)
-		if err != nil {
-			continue // file doesn't exist, skip
-		}
-		storedName := filepath.Base(ef.Path)
-		dstPath := filepath.Join(contextDir, storedName)
-		if err := fileutil.CopyFile(ef.Path, dstPath); err != nil {
-			return nil, fmt.Errorf("copy %s: %w", storedName, err)
+	// 1-2. Snapshot extra files and the filtered .claude/ directory
+	for _, lf := range live {
+		dstPath := filepath.Join(contextDir, filepath.FromSlash(lf.RelPath))
+		if err := fileutil.CopyFile(lf.AbsPath, dstPath); err != nil {
+			return nil, fmt.Errorf("copy %s: %w", lf.RelPath, err)
 		}
-		checksum, _ := FileChecksum(ef.Path)
+		checksum, _ := FileChecksum(lf.AbsPath)
 		files = append(files, FileEntry{
-			RelPath:  storedName,
-			Size:     info.Size(),
-			Mode:     uint32(info.Mode()),
+			RelPath:  lf.RelPath,
+			Size:     lf.Info.Size(),
+			Mode:     uint32(lf.Info.Mode()),
 			Checksum: checksum,
-			Source:   ef.Tag,
+			Source:   lf.Source,
 		})
-		totalSize += info.Size()
-	}
-
-	// 2. Snapshot .claude/ directory (filtered)
-	if _, err := os.Stat(dotClaudeDir); err == nil {
-		walked, err := fileutil.WalkFiltered(
-			dotClaudeDir,
-			cfg.IncludePatterns,
-			cfg.ExcludePatterns,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("walk .claude: %w", err)
-		}
-
-		dotClaudeDst := filepath.Join(contextDir, "dotclaude")
-		for _, w := range walked {
-			dstPath := filepath.Join(dotClaudeDst, w.RelPath)
-			if err := fileutil.CopyFile(w.AbsPath, dstPath); err != nil {
-				return nil, fmt.Errorf("copy %s: %w", w.RelPath, err)
-			}
-				checksum, _ := FileChecksum(w.AbsPath)
-			files = append(files, FileEntry{
-				RelPath:  toSlash(filepath.Join("dotclaude", w.RelPath)),
-				Size:     w.Info.Size(),
-				Mode:     uint32(w.Info.Mode()),
-				Checksum: checksum,
-				Source:   "dotclaude",
-			})
-			totalSize += w.Info.Size()
-		}
+		totalSize += lf.Info.Size()
 	}
 
-	// 3. Extract OAuth email from claude.json (user scope only)
+	// 3. Extract OAuth email from claude.json (user scope, or a custom scope
+	// for another config dir)
 	var oauthEmail string
-	if scope.Type == config.ScopeUser {
-		if ef := scope.ExtraFileByTag("claudejson"); ef != nil {
-			oauthEmail = extractOAuthEmail(ef.Path)
-		}
+	if ef := scope.ExtraFileByTag("claudejson"); ef != nil {
+		oauthEmail = extractOAuthEmail(ef.Path)
 	}
 
 	// 4. Write manifest
@@ -137,6 +137,14 @@ func Save(opts SaveOptions) (*SaveResult, error) {
 		OAuthEmail:  oauthEmail,
 		Scope:       string(scope.Type),
 	}
+	if previousManifest != nil {
+		manifest.CreatedAt = previousManifest.CreatedAt
+		if manifest.Description == "" {
+			manifest.Description = previousManifest.Description
+		}
+		manifest.Tags = previousManifest.Tags
+		manifest.Labels = previousManifest.Labels
+	}
 
 	if err := WriteManifest(contextDir, manifest); err != nil {
 		return nil, err
@@ -147,6 +155,10 @@ func Save(opts SaveOptions) (*SaveResult, error) {
 		return nil, err
 	}
 
+	if err := runHooks(cfg, HookPostSave, hookEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	return &SaveResult{
 		Name:      slug,
 		Dir:       contextDir,
@@ -155,39 +167,46 @@ func Save(opts SaveOptions) (*SaveResult, error) {
 	}, nil
 }
 
-func dryRunSave(slug, dotClaudeDir string, cfg *config.Config, opts SaveOptions) (*SaveResult, error) {
-	var fileCount int
-	var totalSize int64
-
-	for _, ef := range cfg.Scope.ExtraFiles {
-		if info, err := os.Stat(ef.Path); err == nil {
-			fileCount++
-			totalSize += info.Size()
-		}
+func dryRunSave(slug string, cfg *config.Config) (*SaveResult, error) {
+	live, err := collectLiveFiles(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	if _, err := os.Stat(dotClaudeDir); err == nil {
-		walked, err := fileutil.WalkFiltered(
-			dotClaudeDir,
-			cfg.IncludePatterns,
-			cfg.ExcludePatterns,
-		)
-		if err != nil {
-			return nil, err
-		}
-		for _, w := range walked {
-			fileCount++
-			totalSize += w.Info.Size()
-		}
+	var totalSize int64
+	for _, lf := range live {
+		totalSize += lf.Info.Size()
 	}
 
 	return &SaveResult{
 		Name:      slug,
-		Files:     fileCount,
+		Files:     len(live),
 		TotalSize: totalSize,
 	}, nil
 }
 
+// writeSnapshotFile writes data into a context directory at relPath and
+// returns the matching FileEntry.
+func writeSnapshotFile(contextDir, relPath, source string, data []byte, mode os.FileMode) (FileEntry, error) {
+	if mode == 0 {
+		mode = 0644
+	}
+	dst := filepath.Join(contextDir, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return FileEntry{}, fmt.Errorf("create dir for %s: %w", relPath, err)
+	}
+	if err := os.WriteFile(dst, data, mode.Perm()); err != nil {
+		return FileEntry{}, fmt.Errorf("write %s: %w", relPath, err)
+	}
+	return FileEntry{
+		RelPath:  relPath,
+		Size:     int64(len(data)),
+		Mode:     uint32(mode),
+		Checksum: DataChecksum(data),
+		Source:   source,
+	}, nil
+}
+
 func extractOAuthEmail(claudeJSONPath string) string {
 	data, err := os.ReadFile(claudeJSONPath)
 	if err != nil {
@@ -205,10 +224,34 @@ func extractOAuthEmail(claudeJSONPath string) string {
 
 // SetCurrent writes the active context name to the current marker file.
 func SetCurrent(cfg *config.Config, name string) error {
-	if err := os.MkdirAll(cfg.StorageDir, 0755); err != nil {
+	if err := os.MkdirAll(cfg.StateDir(), 0755); err != nil {
 		return err
 	}
-	return os.WriteFile(cfg.CurrentFile(), []byte(name+"\n"), 0644)
+	// Replace the marker atomically: watchers read it concurrently.
+	path := cfg.CurrentFile()
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".current-*")
+	if err != nil {
+		return fmt.Errorf("create temp: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	if _, err := tmpFile.WriteString(name + "\n"); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write temp: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename: %w", err)
+	}
+	return nil
 }
 
 // GetCurrent reads the active context name.
@@ -220,7 +263,5 @@ func GetCurrent(cfg *config.Config) (string, error) {
 		}
 		return "", err
 	}
-	name := string(data)
-	name = name[:len(name)-1] // trim newline
-	return name, nil
+	return strings.TrimSuffix(string(data), "\n"), nil
 }
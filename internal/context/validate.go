@@ -0,0 +1,101 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/schema"
+)
+
+// hasSchema reports whether a stored path is a config file with a known
+// schema. Settings files are only checked at the top of .claude/.
+func hasSchema(relPath string) bool {
+	if rest, ok := strings.CutPrefix(relPath, "dotclaude/"); ok && strings.Contains(rest, "/") {
+		return false
+	}
+	_, ok := schema.For(path.Base(relPath))
+	return ok
+}
+
+// validateFile checks one stored file's contents against its schema.
+func validateFile(relPath string, data []byte) []schema.Issue {
+	issues, err := schema.Validate(relPath, path.Base(relPath), data)
+	if err != nil {
+		return []schema.Issue{{File: relPath, Message: err.Error()}}
+	}
+	return issues
+}
+
+// validateLive checks the live managed files that have a known schema.
+func validateLive(cfg *config.Config) ([]schema.Issue, error) {
+	live, err := collectLiveFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var issues []schema.Issue
+	for _, lf := range live {
+		if !hasSchema(lf.RelPath) {
+			continue
+		}
+		data, err := os.ReadFile(lf.AbsPath)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", lf.RelPath, err)
+		}
+		issues = append(issues, validateFile(lf.RelPath, data)...)
+	}
+	return issues, nil
+}
+
+// Lint validates the JSON config files stored in a saved context.
+func Lint(cfg *config.Config, slug string) ([]schema.Issue, error) {
+	contextDir := filepath.Join(cfg.ContextsDir(), slug)
+	manifest, err := ReadManifest(contextDir)
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w: %w", slug, ErrNotFound, err)
+	}
+	var issues []schema.Issue
+	for _, entry := range manifest.Files {
+		if !hasSchema(entry.RelPath) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(contextDir, filepath.FromSlash(entry.RelPath)))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.RelPath, err)
+		}
+		issues = append(issues, validateFile(entry.RelPath, data)...)
+	}
+	return issues, nil
+}
+
+// InvalidFilesError is returned when validation mode is "error" and managed
+// JSON files fail their schemas.
+type InvalidFilesError struct {
+	Issues []schema.Issue
+}
+
+func (e *InvalidFilesError) Error() string {
+	return fmt.Sprintf("%d schema problem(s) in managed JSON files", len(e.Issues))
+}
+
+// Is makes errors.Is(err, ErrInvalidFiles) match.
+func (e *InvalidFilesError) Is(target error) bool { return target == ErrInvalidFiles }
+
+// enforceValidation applies cfg.Validation to the issues found for an
+// operation. In warn mode problems are printed to stderr; in error mode they
+// abort the operation unless force is set.
+func enforceValidation(cfg *config.Config, issues []schema.Issue, force bool) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", issue)
+	}
+	if cfg.Validation.Action() == config.ValidateError && !force {
+		return &InvalidFilesError{Issues: issues}
+	}
+	return nil
+}
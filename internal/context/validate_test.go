@@ -0,0 +1,72 @@
+package context
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+func TestLint(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "ctx", map[string]string{
+		"settings.json":        `{"permissions":{"allow":"Bash"}}`,
+		"agents/settings.json": `not checked`,
+		".mcp.json":            `{"mcpServers":{}}`,
+	})
+
+	issues, err := Lint(cfg, "ctx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].File != "dotclaude/settings.json" || issues[0].Path != "permissions.allow" {
+		t.Errorf("unexpected issue location: %+v", issues[0])
+	}
+}
+
+func TestSaveValidationModes(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	os.MkdirAll(cfg.Scope.DotClaudeDir, 0755)
+	os.WriteFile(filepath.Join(cfg.Scope.DotClaudeDir, "settings.json"), []byte(`{"model":`), 0644)
+
+	if _, err := Save(SaveOptions{Name: "warned", Config: cfg}); err != nil {
+		t.Fatalf("expected warn mode to save, got %v", err)
+	}
+
+	cfg.Validation.Mode = config.ValidateError
+	_, err := Save(SaveOptions{Name: "refused", Config: cfg})
+	var invalid *InvalidFilesError
+	if !errors.As(err, &invalid) || len(invalid.Issues) != 1 {
+		t.Fatalf("expected InvalidFilesError, got %v", err)
+	}
+	if ContextExists(cfg.ContextsDir(), "refused") {
+		t.Error("expected no context to be saved in error mode")
+	}
+
+	cfg.Validation.Mode = config.ValidateOff
+	if _, err := Save(SaveOptions{Name: "unchecked", Config: cfg}); err != nil {
+		t.Fatalf("expected off mode to save, got %v", err)
+	}
+}
+
+func TestRestoreValidationError(t *testing.T) {
+	cfg := newProjectTestConfig(t)
+	saveProjectContext(t, cfg, "broken", map[string]string{".mcp.json": `{"mcpServers":[]}`})
+	saveProjectContext(t, cfg, "other", map[string]string{"CLAUDE.md": "# Other"})
+	cfg.Validation.Mode = config.ValidateError
+
+	if _, err := Restore(RestoreOptions{Name: "broken", Config: cfg}); err == nil {
+		t.Fatal("expected restore of invalid context to fail")
+	}
+	if current, _ := GetCurrent(cfg); current != "other" {
+		t.Errorf("expected current to stay other, got %q", current)
+	}
+	if _, err := Restore(RestoreOptions{Name: "broken", Force: true, Config: cfg}); err != nil {
+		t.Fatalf("expected --force restore to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Package git reads just enough of a repository's on-disk state (the index)
+// to tell which files are tracked and whether they have uncommitted changes,
+// without running git or touching the network.
+package git
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Index entry flags.
+const (
+	flagAssumeValid  = 0x8000
+	flagExtended     = 0x4000
+	flagNameMask     = 0x0fff
+	extSkipWorktree  = 0x4000
+	extIntentToAdd   = 0x2000
+	entryFixedLength = 40 // ctime, mtime, dev, ino, mode, uid, gid, size
+)
+
+// IndexEntry is a file recorded in the git index.
+type IndexEntry struct {
+	Path         string // slash-separated, relative to the working tree root
+	Mode         uint32
+	Size         uint32 // truncated to 32 bits, like git
+	MtimeSec     uint32
+	MtimeNsec    uint32
+	Hash         []byte
+	Stage        int // non-zero during a merge conflict
+	AssumeValid  bool
+	SkipWorktree bool
+	IntentToAdd  bool
+}
+
+// Index is a parsed git index file.
+type Index struct {
+	Version int
+	Entries []IndexEntry
+}
+
+// ReadIndex parses the index file at path. hashSize is the object hash length
+// of the repository: 20 for SHA-1, 32 for SHA-256.
+func ReadIndex(path string, hashSize int) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := parseIndex(data, hashSize)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return idx, nil
+}
+
+func parseIndex(data []byte, hashSize int) (*Index, error) {
+	if len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil, errors.New("not a git index")
+	}
+	idx := &Index{Version: int(binary.BigEndian.Uint32(data[4:8]))}
+	if idx.Version < 2 || idx.Version > 4 {
+		return nil, fmt.Errorf("unsupported index version %d", idx.Version)
+	}
+	count := int(binary.BigEndian.Uint32(data[8:12]))
+
+	off := 12
+	var prev string
+	for i := 0; i < count; i++ {
+		start := off
+		fixed := entryFixedLength + hashSize + 2
+		if off+fixed > len(data) {
+			return nil, errors.New("truncated index entry")
+		}
+		be := binary.BigEndian
+		e := IndexEntry{
+			MtimeSec:  be.Uint32(data[off+8:]),
+			MtimeNsec: be.Uint32(data[off+12:]),
+			Mode:      be.Uint32(data[off+24:]),
+			Size:      be.Uint32(data[off+36:]),
+			Hash:      bytes.Clone(data[off+entryFixedLength : off+entryFixedLength+hashSize]),
+		}
+		flags := be.Uint16(data[off+entryFixedLength+hashSize:])
+		off += fixed
+		e.AssumeValid = flags&flagAssumeValid != 0
+		e.Stage = int(flags>>12) & 0x3
+		if flags&flagExtended != 0 {
+			if idx.Version < 3 {
+				return nil, errors.New("extended flags in a version 2 index")
+			}
+			if off+2 > len(data) {
+				return nil, errors.New("truncated index entry")
+			}
+			ext := be.Uint16(data[off:])
+			off += 2
+			e.SkipWorktree = ext&extSkipWorktree != 0
+			e.IntentToAdd = ext&extIntentToAdd != 0
+		}
+
+		if idx.Version == 4 {
+			// The path is stored as the number of bytes to drop from the
+			// previous path followed by the NUL-terminated suffix.
+			strip, n := readOffset(data[off:])
+			if n == 0 || strip > len(prev) {
+				return nil, errors.New("invalid path prefix in index entry")
+			}
+			off += n
+			end := bytes.IndexByte(data[off:], 0)
+			if end < 0 {
+				return nil, errors.New("unterminated path in index entry")
+			}
+			e.Path = prev[:len(prev)-strip] + string(data[off:off+end])
+			off += end + 1
+		} else {
+			nameLen := int(flags & flagNameMask)
+			end := bytes.IndexByte(data[off:], 0)
+			if end < 0 {
+				return nil, errors.New("unterminated path in index entry")
+			}
+			if nameLen < flagNameMask && end != nameLen {
+				return nil, errors.New("path length mismatch in index entry")
+			}
+			e.Path = string(data[off : off+end])
+			// Entries are NUL-padded to a multiple of eight bytes.
+			off = start + (off-start+end+8)&^7
+			if off > len(data) {
+				return nil, errors.New("truncated index entry")
+			}
+		}
+		prev = e.Path
+		idx.Entries = append(idx.Entries, e)
+	}
+	return idx, nil
+}
+
+// readOffset decodes git's variable-length offset encoding, returning the
+// value and the number of bytes read (0 if data is truncated).
+func readOffset(data []byte) (int, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	c := data[0]
+	val := int(c & 0x7f)
+	n := 1
+	for c&0x80 != 0 {
+		if n >= len(data) {
+			return 0, 0
+		}
+		c = data[n]
+		n++
+		val = ((val + 1) << 7) | int(c&0x7f)
+	}
+	return val, n
+}
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// initRepo creates a repository with a.txt and dir/b.txt committed and an
+// untracked c.txt.
+func initRepo(t *testing.T, initArgs ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, append([]string{"init", "-q"}, initArgs...)...)
+	os.MkdirAll(filepath.Join(dir, "dir"), 0755)
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "dir", "b.txt"), []byte("b\n"), 0644)
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "init")
+	os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c\n"), 0644)
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestReadIndexVersions(t *testing.T) {
+	for _, version := range []string{"2", "3", "4"} {
+		t.Run("v"+version, func(t *testing.T) {
+			dir := initRepo(t)
+			if version != "2" {
+				// Extended flags need version 3; git writes 2 otherwise.
+				runGit(t, dir, "update-index", "--skip-worktree", "dir/b.txt")
+			}
+			runGit(t, dir, "update-index", "--index-version", version)
+
+			idx, err := ReadIndex(filepath.Join(dir, ".git", "index"), 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := int(version[0] - '0'); idx.Version != want {
+				t.Errorf("Version = %d, want %d", idx.Version, want)
+			}
+			var paths []string
+			for _, e := range idx.Entries {
+				paths = append(paths, e.Path)
+			}
+			if len(paths) != 2 || paths[0] != "a.txt" || paths[1] != "dir/b.txt" {
+				t.Errorf("paths = %v", paths)
+			}
+			if skip := version != "2"; len(idx.Entries) == 2 && (idx.Entries[0].SkipWorktree || idx.Entries[1].SkipWorktree != skip) {
+				t.Errorf("SkipWorktree = %v, %v; want false, %v", idx.Entries[0].SkipWorktree, idx.Entries[1].SkipWorktree, skip)
+			}
+		})
+	}
+}
+
+func TestReadIndexInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	os.WriteFile(path, []byte("not an index"), 0644)
+	if _, err := ReadIndex(path, 20); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestRepoTrackedAndModified(t *testing.T) {
+	dir := initRepo(t)
+	sub := filepath.Join(dir, "dir")
+	repo, err := Open(sub) // finds the root from a subdirectory
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b, c := filepath.Join(dir, "a.txt"), filepath.Join(sub, "b.txt"), filepath.Join(dir, "c.txt")
+	if !repo.Tracked(a) || !repo.Tracked(b) || repo.Tracked(c) {
+		t.Errorf("Tracked: a=%v b=%v c=%v", repo.Tracked(a), repo.Tracked(b), repo.Tracked(c))
+	}
+	for _, path := range []string{a, b, c} {
+		if m, err := repo.Modified(path); err != nil || m {
+			t.Errorf("Modified(%s) = %v, %v; want false", path, m, err)
+		}
+	}
+
+	// Same size, different content: caught by hashing.
+	os.WriteFile(a, []byte("x\n"), 0644)
+	if m, err := repo.Modified(a); err != nil || !m {
+		t.Errorf("Modified(a) after edit = %v, %v; want true", m, err)
+	}
+
+	// Marked skip-worktree: git ignores the change, so do we.
+	runGit(t, dir, "update-index", "--skip-worktree", "a.txt")
+	repo, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !repo.SkipWorktree(a) {
+		t.Error("expected a.txt to be skip-worktree")
+	}
+	if m, _ := repo.Modified(a); m {
+		t.Error("skip-worktree file reported as modified")
+	}
+}
+
+func TestRepoSHA256(t *testing.T) {
+	dir := initRepo(t, "--object-format=sha256")
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := filepath.Join(dir, "a.txt")
+	if !repo.Tracked(a) {
+		t.Fatal("expected a.txt to be tracked")
+	}
+	// Touch without changing content: the hash must still match.
+	os.WriteFile(a, []byte("a\n"), 0644)
+	if m, err := repo.Modified(a); err != nil || m {
+		t.Errorf("Modified = %v, %v; want false", m, err)
+	}
+}
+
+func TestRepoSetSkipWorktree(t *testing.T) {
+	dir := initRepo(t)
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetSkipWorktree([]string{filepath.Join(dir, "dir", "b.txt")}); err != nil {
+		t.Fatal(err)
+	}
+	repo, _ = Open(dir)
+	if !repo.SkipWorktree(filepath.Join(dir, "dir", "b.txt")) {
+		t.Error("expected dir/b.txt to be skip-worktree")
+	}
+}
@@ -0,0 +1,179 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pfldy2850/claudectx/internal/claude"
+)
+
+// Repo is a git working tree together with its index.
+type Repo struct {
+	*claude.GitRepo
+	newHash func() hash.Hash
+	entries map[string]*IndexEntry
+}
+
+// Open finds the git working tree containing dir and reads its index. A
+// repository without an index (nothing staged yet) tracks no files.
+func Open(dir string) (*Repo, error) {
+	gr, err := claude.FindGitRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Repo{GitRepo: gr, newHash: sha1.New, entries: map[string]*IndexEntry{}}
+	hashSize := sha1.Size
+	if objectFormat(gr.CommonDir) == "sha256" {
+		r.newHash = sha256.New
+		hashSize = sha256.Size
+	}
+
+	idx, err := ReadIndex(filepath.Join(gr.GitDir, "index"), hashSize)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range idx.Entries {
+		e := &idx.Entries[i]
+		if e.Stage <= 1 { // during a conflict, stage 1 is the common ancestor
+			r.entries[e.Path] = e
+		}
+	}
+	return r, nil
+}
+
+// objectFormat returns extensions.objectFormat from the repository config,
+// or "" for the default (SHA-1).
+func objectFormat(gitDir string) string {
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.ToLower(strings.Trim(line, "[] \t"))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && section == "extensions" && strings.EqualFold(strings.TrimSpace(key), "objectformat") {
+			return strings.ToLower(strings.TrimSpace(value))
+		}
+	}
+	return ""
+}
+
+// rel returns the index path of an absolute file path, or false if it lies
+// outside the working tree.
+func (r *Repo) rel(path string) (string, bool) {
+	rel, err := filepath.Rel(r.Root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// Tracked reports whether the file at path is in the index.
+func (r *Repo) Tracked(path string) bool {
+	rel, ok := r.rel(path)
+	if !ok {
+		return false
+	}
+	e, ok := r.entries[rel]
+	return ok && !e.IntentToAdd
+}
+
+// SkipWorktree reports whether the file at path is marked skip-worktree.
+func (r *Repo) SkipWorktree(path string) bool {
+	rel, ok := r.rel(path)
+	if !ok {
+		return false
+	}
+	e, ok := r.entries[rel]
+	return ok && e.SkipWorktree
+}
+
+// Modified reports whether the tracked file at path differs from the index,
+// as `git status` would show it. Files git itself ignores changes to
+// (skip-worktree, assume-unchanged) are not modified; neither are missing
+// files. Clean/smudge filters and line-ending conversion are not applied.
+func (r *Repo) Modified(path string) (bool, error) {
+	rel, ok := r.rel(path)
+	if !ok {
+		return false, nil
+	}
+	e, ok := r.entries[rel]
+	if !ok || e.IntentToAdd || e.SkipWorktree || e.AssumeValid {
+		return false, nil
+	}
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if uint32(info.Size()) != e.Size {
+		return true, nil
+	}
+	mtime := info.ModTime()
+	if uint32(mtime.Unix()) == e.MtimeSec && uint32(mtime.Nanosecond()) == e.MtimeNsec {
+		return false, nil // unchanged since it was staged
+	}
+	sum, err := r.blobHash(path, info.Size())
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(sum, e.Hash), nil
+}
+
+// blobHash computes the object ID git would assign the file's content.
+func (r *Repo) blobHash(path string, size int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := r.newHash()
+	h.Write([]byte("blob " + strconv.FormatInt(size, 10) + "\x00"))
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// SetSkipWorktree runs `git update-index --skip-worktree` for the given
+// tracked files, so local rewrites stay out of `git status` and commits.
+func (r *Repo) SetSkipWorktree(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	args := []string{"-C", r.Root, "update-index", "--skip-worktree", "--"}
+	for _, p := range paths {
+		rel, ok := r.rel(p)
+		if !ok {
+			return fmt.Errorf("%s is outside %s", p, r.Root)
+		}
+		args = append(args, rel)
+	}
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git update-index --skip-worktree: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
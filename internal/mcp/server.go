@@ -0,0 +1,396 @@
+// Package mcp implements `claudectx mcp`: a Model Context Protocol server
+// speaking newline-delimited JSON-RPC 2.0 over stdio. It exposes saved
+// contexts as tools so Claude Code can list, inspect and compare them, and
+// (when allowed) switch between them.
+package mcp
+
+import (
+	"bufio"
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+)
+
+// Protocol versions this server understands, newest first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// maxMessageSize bounds a single JSON-RPC message read from stdin.
+const maxMessageSize = 10 << 20
+
+// Scope pairs a scope name with its config.
+type Scope struct {
+	Name   string
+	Config *config.Config
+}
+
+// Server answers MCP requests for a set of scopes.
+type Server struct {
+	Scopes      []Scope // searched in order when a tool call names no scope
+	AllowSwitch bool    // expose switch_context
+	Version     string  // reported as serverInfo.version
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted or ctx is done. Notifications get no response.
+func (s *Server) Serve(ctx gocontext.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	enc := json.NewEncoder(out)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := enc.Encode(errorResponse(json.RawMessage("null"), codeParseError, err.Error())); err != nil {
+				return err
+			}
+			continue
+		}
+		resp := s.handle(ctx, &req)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle dispatches one request. It returns nil for notifications.
+func (s *Server) handle(ctx gocontext.Context, req *request) *response {
+	if req.ID == nil {
+		return nil // notification, e.g. notifications/initialized
+	}
+	if req.JSONRPC != "2.0" {
+		return errorResponse(req.ID, codeInvalidRequest, `jsonrpc must be "2.0"`)
+	}
+
+	var result any
+	var err *rpcError
+	switch req.Method {
+	case "initialize":
+		result, err = s.initialize(req.Params)
+	case "ping":
+		result = struct{}{}
+	case "tools/list":
+		result = map[string]any{"tools": s.tools()}
+	case "tools/call":
+		result, err = s.callTool(ctx, req.Params)
+	default:
+		err = &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+	if err != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: err}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: msg}}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+	version := protocolVersions[0]
+	if slices.Contains(protocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]any{"name": "claudectx", "version": s.Version},
+	}, nil
+}
+
+// tool describes a tool in tools/list.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+func objectSchema(required []string, props map[string]any) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProp(desc string) map[string]any {
+	return map[string]any{"type": "string", "description": desc}
+}
+
+func (s *Server) tools() []tool {
+	scopeProp := stringProp("Scope to use: 'user', 'project', 'local', 'managed' or a custom scope. Defaults to the first scope that has the context.")
+	tools := []tool{
+		{
+			Name:        "list_contexts",
+			Description: "List saved claudectx contexts with their description, file count, MCP servers and whether they are active.",
+			InputSchema: objectSchema(nil, map[string]any{
+				"scope": stringProp("Only list this scope: 'user', 'project', 'local', 'managed' or a custom scope."),
+			}),
+		},
+		{
+			Name:        "show_context",
+			Description: "Show a saved context: its manifest (files, sizes, scope), the MCP servers it configures and whether it is active.",
+			InputSchema: objectSchema([]string{"name"}, map[string]any{
+				"name":  stringProp("Context name."),
+				"scope": scopeProp,
+			}),
+		},
+		{
+			Name:        "diff_contexts",
+			Description: "Compare two saved contexts: files only in one of them, files whose content differs, and MCP servers only in one of them.",
+			InputSchema: objectSchema([]string{"a", "b"}, map[string]any{
+				"a":     stringProp("First context name."),
+				"b":     stringProp("Second context name."),
+				"scope": scopeProp,
+			}),
+		},
+	}
+	if s.AllowSwitch {
+		tools = append(tools, tool{
+			Name:        "switch_context",
+			Description: "Switch the active context: back up and replace the live Claude Code configuration with a saved context.",
+			InputSchema: objectSchema([]string{"name"}, map[string]any{
+				"name":  stringProp("Context name."),
+				"scope": scopeProp,
+			}),
+		})
+	}
+	return tools
+}
+
+// toolArgs are the arguments accepted by the tools.
+type toolArgs struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+func (s *Server) callTool(ctx gocontext.Context, params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	var args toolArgs
+	if len(p.Arguments) > 0 {
+		if err := json.Unmarshal(p.Arguments, &args); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+
+	var result any
+	var err error
+	switch p.Name {
+	case "list_contexts":
+		result, err = s.listContexts(args)
+	case "show_context":
+		result, err = s.showContext(args)
+	case "diff_contexts":
+		result, err = s.diffContexts(args)
+	case "switch_context":
+		if !s.AllowSwitch {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "switch_context is disabled; start the server with --allow-switch"}
+		}
+		result, err = s.switchContext(ctx, args)
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool %q", p.Name)}
+	}
+	if err != nil {
+		// Tool failures are reported to the model, not as protocol errors.
+		return toolResult(err.Error(), true), nil
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolResult(err.Error(), true), nil
+	}
+	return toolResult(string(data), false), nil
+}
+
+func toolResult(text string, isError bool) map[string]any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+// findScope returns the scope named scope, or the first scope containing
+// every context in names.
+func (s *Server) findScope(scope string, names ...string) (*Scope, error) {
+	for i := range s.Scopes {
+		sc := &s.Scopes[i]
+		if scope != "" {
+			if sc.Name == scope {
+				return sc, nil
+			}
+			continue
+		}
+		found := true
+		for _, name := range names {
+			if !context.ContextExists(sc.Config.ContextsDir(), context.Slugify(name)) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return sc, nil
+		}
+	}
+	if scope != "" {
+		return nil, fmt.Errorf("scope %q is not available", scope)
+	}
+	return nil, fmt.Errorf("context %q %w", names[len(names)-1], context.ErrNotFound)
+}
+
+// contextSummary is an entry of list_contexts.
+type contextSummary struct {
+	Scope       string   `json:"scope"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Files       int      `json:"files"`
+	Current     bool     `json:"current"`
+	Locked      bool     `json:"locked,omitempty"`
+	MCPServers  []string `json:"mcpServers"`
+}
+
+func (s *Server) listContexts(args toolArgs) (any, error) {
+	if args.Scope != "" {
+		if _, err := s.findScope(args.Scope); err != nil {
+			return nil, err
+		}
+	}
+	out := []contextSummary{}
+	for _, sc := range s.Scopes {
+		if args.Scope != "" && sc.Name != args.Scope {
+			continue
+		}
+		names, err := context.ListContexts(sc.Config.ContextsDir())
+		if err != nil {
+			return nil, err
+		}
+		current, _ := context.GetCurrent(sc.Config)
+		for _, name := range names {
+			m, err := context.ReadManifest(filepath.Join(sc.Config.ContextsDir(), name))
+			if err != nil {
+				continue
+			}
+			servers, _ := context.MCPServers(sc.Config, name)
+			out = append(out, contextSummary{
+				Scope:       sc.Name,
+				Name:        m.Name,
+				Description: m.Description,
+				Files:       len(m.Files),
+				Current:     name == current,
+				Locked:      m.Locked,
+				MCPServers:  servers,
+			})
+		}
+	}
+	return out, nil
+}
+
+// contextDetails is the result of show_context.
+type contextDetails struct {
+	*context.Manifest
+	Current    bool     `json:"current"`
+	MCPServers []string `json:"mcpServers"`
+}
+
+func (s *Server) showContext(args toolArgs) (any, error) {
+	if args.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	sc, err := s.findScope(args.Scope, args.Name)
+	if err != nil {
+		return nil, err
+	}
+	slug := context.Slugify(args.Name)
+	m, err := context.ReadManifest(filepath.Join(sc.Config.ContextsDir(), slug))
+	if err != nil {
+		return nil, fmt.Errorf("context %q %w", slug, context.ErrNotFound)
+	}
+	servers, err := context.MCPServers(sc.Config, slug)
+	if err != nil {
+		return nil, err
+	}
+	current, _ := context.GetCurrent(sc.Config)
+	return contextDetails{Manifest: m, Current: slug == current, MCPServers: servers}, nil
+}
+
+func (s *Server) diffContexts(args toolArgs) (any, error) {
+	if args.A == "" || args.B == "" {
+		return nil, errors.New("a and b are required")
+	}
+	sc, err := s.findScope(args.Scope, args.A, args.B)
+	if err != nil {
+		return nil, err
+	}
+	return context.CompareContexts(sc.Config, context.Slugify(args.A), context.Slugify(args.B))
+}
+
+func (s *Server) switchContext(ctx gocontext.Context, args toolArgs) (any, error) {
+	if args.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	sc, err := s.findScope(args.Scope, args.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return context.Restore(context.RestoreOptions{Name: args.Name, Config: sc.Config})
+}
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"bufio"
+	gocontext "context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+)
+
+// rpcClient drives a Server in-process over pipes.
+type rpcClient struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	nextID int
+}
+
+func newRPCClient(t *testing.T, srv *Server) *rpcClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go func() {
+		srv.Serve(gocontext.Background(), inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return &rpcClient{t: t, in: inW, out: bufio.NewScanner(outR)}
+}
+
+func (c *rpcClient) notify(method string) {
+	c.t.Helper()
+	c.write(map[string]any{"jsonrpc": "2.0", "method": method})
+}
+
+func (c *rpcClient) write(msg any) {
+	c.t.Helper()
+	data, _ := json.Marshal(msg)
+	if _, err := c.in.Write(append(data, '\n')); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// call sends a request and decodes the response's result or error.
+func (c *rpcClient) call(method string, params any) (json.RawMessage, *rpcError) {
+	c.t.Helper()
+	c.nextID++
+	c.write(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	if !c.out.Scan() {
+		c.t.Fatalf("no response to %s", method)
+	}
+	var resp struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(c.out.Bytes(), &resp); err != nil {
+		c.t.Fatalf("invalid response %q: %v", c.out.Text(), err)
+	}
+	if resp.ID != c.nextID {
+		c.t.Fatalf("response id %d, want %d", resp.ID, c.nextID)
+	}
+	return resp.Result, resp.Error
+}
+
+// callTool calls a tool and returns its text content and isError flag.
+func (c *rpcClient) callTool(name string, args map[string]any) (string, bool) {
+	c.t.Helper()
+	raw, rpcErr := c.call("tools/call", map[string]any{"name": name, "arguments": args})
+	if rpcErr != nil {
+		c.t.Fatalf("tools/call %s: %s", name, rpcErr.Message)
+	}
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	json.Unmarshal(raw, &result)
+	if len(result.Content) != 1 {
+		c.t.Fatalf("unexpected tool result %s", raw)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+func newTestScope(t *testing.T) Scope {
+	t.Helper()
+	root := t.TempDir()
+	cfg, err := config.LoadWithScope("", config.ProjectScopeAt(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(name, mcpJSON string) {
+		os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# "+name), 0644)
+		os.WriteFile(filepath.Join(root, ".mcp.json"), []byte(mcpJSON), 0644)
+		if _, err := context.Save(context.SaveOptions{Name: name, Config: cfg}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("work", `{"mcpServers": {"jira": {"command": "jira-mcp"}, "github": {"command": "gh-mcp"}}}`)
+	save("personal", `{"mcpServers": {"github": {"command": "gh-mcp"}}}`)
+	return Scope{Name: "project", Config: cfg}
+}
+
+func TestInitializeAndListTools(t *testing.T) {
+	c := newRPCClient(t, &Server{Scopes: []Scope{newTestScope(t)}, Version: "test"})
+
+	raw, rpcErr := c.call("initialize", map[string]any{"protocolVersion": "2024-11-05", "capabilities": map[string]any{}})
+	if rpcErr != nil {
+		t.Fatal(rpcErr.Message)
+	}
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	json.Unmarshal(raw, &init)
+	if init.ProtocolVersion != "2024-11-05" || init.ServerInfo.Name != "claudectx" {
+		t.Errorf("unexpected initialize result: %s", raw)
+	}
+	c.notify("notifications/initialized")
+
+	raw, _ = c.call("tools/list", nil)
+	var list struct {
+		Tools []tool `json:"tools"`
+	}
+	json.Unmarshal(raw, &list)
+	var names []string
+	for _, tl := range list.Tools {
+		names = append(names, tl.Name)
+	}
+	if strings.Join(names, ",") != "list_contexts,show_context,diff_contexts" {
+		t.Errorf("unexpected tools without --allow-switch: %v", names)
+	}
+
+	if _, rpcErr := c.call("resources/list", nil); rpcErr == nil || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("expected method not found, got %+v", rpcErr)
+	}
+	if _, rpcErr := c.call("tools/call", map[string]any{"name": "switch_context", "arguments": map[string]any{"name": "work"}}); rpcErr == nil {
+		t.Error("expected switch_context to be refused without AllowSwitch")
+	}
+}
+
+func TestTools(t *testing.T) {
+	scope := newTestScope(t)
+	c := newRPCClient(t, &Server{Scopes: []Scope{scope}, AllowSwitch: true})
+
+	text, isErr := c.callTool("list_contexts", nil)
+	var list []contextSummary
+	json.Unmarshal([]byte(text), &list)
+	if isErr || len(list) != 2 || list[1].Name != "work" || strings.Join(list[1].MCPServers, ",") != "github,jira" {
+		t.Errorf("unexpected list_contexts result: %s", text)
+	}
+
+	text, isErr = c.callTool("show_context", map[string]any{"name": "personal"})
+	if isErr || !strings.Contains(text, `"current": true`) || !strings.Contains(text, `"github"`) {
+		t.Errorf("unexpected show_context result: %s", text)
+	}
+
+	text, isErr = c.callTool("diff_contexts", map[string]any{"a": "work", "b": "personal"})
+	var diff context.ContextDiff
+	json.Unmarshal([]byte(text), &diff)
+	if isErr || strings.Join(diff.ServersOnlyInA, ",") != "jira" || len(diff.Modified) != 2 {
+		t.Errorf("unexpected diff_contexts result: %s", text)
+	}
+
+	if text, isErr = c.callTool("show_context", map[string]any{"name": "missing"}); !isErr {
+		t.Errorf("expected show_context of a missing context to fail, got %s", text)
+	}
+
+	if text, isErr = c.callTool("switch_context", map[string]any{"name": "work"}); isErr {
+		t.Fatalf("switch_context failed: %s", text)
+	}
+	if current, _ := context.GetCurrent(scope.Config); current != "work" {
+		t.Errorf("current = %q after switch_context", current)
+	}
+}
@@ -0,0 +1,114 @@
+// Package projects keeps a registry of the project roots where claudectx has
+// been used, so project contexts can be listed across repositories.
+package projects
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+)
+
+// Project is a registered project root.
+type Project struct {
+	Root     string    `json:"root"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// RegistryFile returns the registry path, projects.json in the user storage dir.
+func RegistryFile() (string, error) {
+	dir, err := config.DefaultStorageDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "projects.json"), nil
+}
+
+// Load reads the registry at path, sorted by root. A missing registry is empty.
+func Load(path string) ([]Project, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Project
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Root < list[j].Root })
+	return list, nil
+}
+
+// Register adds root to the registry at path, or refreshes its LastUsed time.
+func Register(path, root string, now time.Time) error {
+	list, err := Load(path)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		if list[i].Root == root {
+			list[i].LastUsed = now
+			return save(path, list)
+		}
+	}
+	return save(path, append(list, Project{Root: root, LastUsed: now}))
+}
+
+// Prune removes the projects whose root directory no longer exists and
+// returns the remaining and the removed projects.
+func Prune(path string) (kept, removed []Project, err error) {
+	list, err := Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range list {
+		if info, err := os.Stat(p.Root); err == nil && info.IsDir() {
+			kept = append(kept, p)
+		} else {
+			removed = append(removed, p)
+		}
+	}
+	if len(removed) > 0 {
+		if err := save(path, kept); err != nil {
+			return nil, nil, err
+		}
+	}
+	return kept, removed, nil
+}
+
+// save writes the registry atomically, so concurrent readers never see it torn.
+func save(path string, list []Project) error {
+	if list == nil {
+		list = []Project{}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Root < list[j].Root })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".projects-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,55 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRegisterAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "storage", "projects.json")
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	os.Mkdir(a, 0755)
+	os.Mkdir(b, 0755)
+
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, root := range []string{b, a, b} {
+		if err := Register(path, root, t0); err != nil {
+			t.Fatal(err)
+		}
+		t0 = t0.Add(time.Hour)
+	}
+
+	list, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Root != a || list[1].Root != b {
+		t.Fatalf("Load = %+v, want a and b once each", list)
+	}
+	if !list[1].LastUsed.Equal(t0.Add(-time.Hour)) {
+		t.Errorf("LastUsed of b = %v, want refreshed to the last registration", list[1].LastUsed)
+	}
+
+	os.RemoveAll(a)
+	kept, removed, err := Prune(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept) != 1 || kept[0].Root != b || len(removed) != 1 || removed[0].Root != a {
+		t.Errorf("Prune = %+v, %+v", kept, removed)
+	}
+	if list, _ := Load(path); len(list) != 1 {
+		t.Errorf("registry after prune = %+v", list)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	list, err := Load(filepath.Join(t.TempDir(), "projects.json"))
+	if err != nil || list != nil {
+		t.Errorf("Load = %v, %v; want empty", list, err)
+	}
+}
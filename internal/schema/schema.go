@@ -0,0 +1,305 @@
+// Package schema validates Claude Code's JSON config files against embedded
+// JSON schemas. Only the subset of JSON Schema used by the bundled schemas is
+// supported: type, enum, properties, required, additionalProperties, items,
+// and local $ref into definitions.
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//go:embed schemas/*.json
+var schemasFS embed.FS
+
+// fileSchemas maps the base name of a known config file to its schema.
+var fileSchemas = map[string]string{
+	"settings.json":       "settings",
+	"settings.local.json": "settings",
+	".mcp.json":           "mcp",
+	".claude.json":        "claude",
+	"claude.json":         "claude",
+}
+
+// For returns the schema name for a config file base name, if it has one.
+func For(fileName string) (string, bool) {
+	name, ok := fileSchemas[fileName]
+	return name, ok
+}
+
+// Issue is a single validation problem. Path is a key path inside the
+// document such as "permissions.allow[0]"; empty means the document root.
+type Issue struct {
+	File    string `json:"file"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Path == "" {
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.File, i.Path, i.Message)
+}
+
+// Schema is a parsed JSON schema.
+type Schema struct {
+	Description          string             `json:"description,omitempty"`
+	Type                 typeList           `json:"type,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+
+	reject bool // the boolean schema false
+}
+
+// UnmarshalJSON accepts boolean schemas (true allows anything, false nothing).
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		*s = Schema{}
+		return nil
+	case "false":
+		*s = Schema{reject: true}
+		return nil
+	}
+	type plain Schema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// typeList accepts "type" as either a single name or a list of names.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = typeList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = many
+	return nil
+}
+
+// Load returns the embedded schema with the given name.
+func Load(name string) (*Schema, error) {
+	data, err := schemasFS.ReadFile("schemas/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown schema %q", name)
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse schema %q: %w", name, err)
+	}
+	return &s, nil
+}
+
+// Validate checks a config file's contents against the schema for its base
+// name. Files without a known schema are not checked.
+func Validate(file, baseName string, data []byte) ([]Issue, error) {
+	name, ok := For(baseName)
+	if !ok {
+		return nil, nil
+	}
+	s, err := Load(name)
+	if err != nil {
+		return nil, err
+	}
+	issues := s.Validate(data)
+	for i := range issues {
+		issues[i].File = file
+	}
+	return issues, nil
+}
+
+// Validate parses data as JSON and checks it against the schema. A syntax
+// error is reported as a single issue with its line and column.
+func (s *Schema) Validate(data []byte) []Issue {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return []Issue{{Message: syntaxMessage(data, err)}}
+	}
+	if dec.More() {
+		return []Issue{{Message: "invalid JSON: unexpected data after top-level value"}}
+	}
+
+	var issues []Issue
+	s.check(s, v, "", &issues)
+	return issues
+}
+
+func (s *Schema) check(root *Schema, v any, path string, issues *[]Issue) {
+	if s.Ref != "" {
+		ref, err := root.resolve(s.Ref)
+		if err != nil {
+			*issues = append(*issues, Issue{Path: path, Message: err.Error()})
+			return
+		}
+		s = ref
+	}
+	if s.reject {
+		*issues = append(*issues, Issue{Path: path, Message: "key is not allowed"})
+		return
+	}
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		*issues = append(*issues, Issue{
+			Path:    path,
+			Message: fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, " or "), typeOf(v)),
+		})
+		return
+	}
+	if len(s.Enum) > 0 && !enumContains(s.Enum, v) {
+		*issues = append(*issues, Issue{
+			Path:    path,
+			Message: fmt.Sprintf("must be one of %s", formatEnum(s.Enum)),
+		})
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := val[key]; !ok {
+				*issues = append(*issues, Issue{Path: path, Message: fmt.Sprintf("missing required key %q", key)})
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := joinKey(path, k)
+			if prop, ok := s.Properties[k]; ok {
+				prop.check(root, val[k], child, issues)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.check(root, val[k], child, issues)
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				s.Items.check(root, item, fmt.Sprintf("%s[%d]", path, i), issues)
+			}
+		}
+	}
+}
+
+// resolve looks up a local "#/definitions/<name>" reference.
+func (s *Schema) resolve(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/definitions/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	def, ok := s.Definitions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown $ref %q", ref)
+	}
+	return def, nil
+}
+
+func (t typeList) matches(v any) bool {
+	got := typeOf(v)
+	for _, want := range t {
+		if want == got || (want == "number" && got == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type name of a decoded value.
+func typeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func enumContains(enum []any, v any) bool {
+	for _, e := range enum {
+		if n, ok := v.(json.Number); ok {
+			if f, err := n.Float64(); err == nil && reflect.DeepEqual(e, f) {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		data, _ := json.Marshal(e)
+		parts[i] = string(data)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// syntaxMessage describes a JSON decode error with its line and column.
+func syntaxMessage(data []byte, err error) string {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, col := lineCol(data, syntaxErr.Offset)
+		return fmt.Sprintf("invalid JSON at line %d, column %d: %v", line, col, err)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || err == io.EOF {
+		return "invalid JSON: unexpected end of input"
+	}
+	return fmt.Sprintf("invalid JSON: %v", err)
+}
+
+// lineCol converts a SyntaxError offset, which counts the offending byte,
+// into a 1-based line and column.
+func lineCol(data []byte, offset int64) (line, col int) {
+	if offset > 0 {
+		offset--
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string // "path: message" substrings, in order
+	}{
+		{"valid", `{"model":"opus","permissions":{"allow":["Bash(ls)"],"defaultMode":"plan"},"custom":1}`, nil},
+		{"wrong type", `{"permissions":{"allow":["Bash(ls)", 3]}}`, []string{"permissions.allow[1]: expected string, got integer"}},
+		{"enum", `{"forceLoginMethod":"yolo"}`, []string{`forceLoginMethod: must be one of "claudeai"`}},
+		{"newer values", `{"permissions":{"defaultMode":"dontAsk"},"hooks":{"Stop":[{"hooks":[{"type":"prompt","prompt":"done?"}]}]}}`, nil},
+		{"env map", `{"env":{"A":"1","B":2}}`, []string{"env.B: expected string, got integer"}},
+		{"hook required", `{"hooks":{"PreToolUse":[{"matcher":"Bash","hooks":[{"command":"x"}]}]}}`, []string{`hooks.PreToolUse[0].hooks[0]: missing required key "type"`}},
+		{"not an object", `[]`, []string{"expected object, got array"}},
+		{"syntax", "{\n  \"model\": \"opus\",\n}", []string{"invalid JSON at line 3, column 1"}},
+		{"empty", ``, []string{"unexpected end of input"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := Validate("dotclaude/settings.json", "settings.json", []byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("expected %d issues, got %v", len(tt.want), issues)
+			}
+			for i, want := range tt.want {
+				got := issues[i].String()
+				if !strings.HasPrefix(got, "dotclaude/settings.json: ") || !strings.Contains(got, want) {
+					t.Errorf("issue %d = %q, want it to contain %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMCP(t *testing.T) {
+	issues, err := Validate(".mcp.json", ".mcp.json", []byte(`{"mcpServers":{"api":{"type":"ws","args":"x"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.Path+": "+i.Message)
+	}
+	want := []string{"mcpServers.api.args: expected array, got string", `mcpServers.api.type: must be one of "stdio", "sse", "http"`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	issues, _ = Validate(".mcp.json", ".mcp.json", []byte(`{}`))
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, `missing required key "mcpServers"`) {
+		t.Errorf("expected missing mcpServers, got %v", issues)
+	}
+}
+
+func TestValidateUnknownFile(t *testing.T) {
+	issues, err := Validate("notes.json", "notes.json", []byte(`not json`))
+	if err != nil || issues != nil {
+		t.Errorf("expected unknown files to be skipped, got %v, %v", issues, err)
+	}
+}
+
+func TestBundledSchemasLoad(t *testing.T) {
+	for _, name := range []string{"settings", "mcp", "claude"} {
+		if _, err := Load(name); err != nil {
+			t.Errorf("Load(%q): %v", name, err)
+		}
+	}
+}
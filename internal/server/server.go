@@ -0,0 +1,344 @@
+// Package server implements the HTTP/JSON API of `claudectx serve`.
+//
+//	GET  /v1/scopes                  scopes served, in preference order
+//	GET  /v1/contexts?scope=         saved contexts of a scope
+//	GET  /v1/current?scope=          active context of a scope
+//	GET  /v1/status?scope=&name=     drift between live files and a context
+//	                                 (the active one if name is empty)
+//	POST /v1/switch                  {"scope", "name", "force"}
+//	POST /v1/save                    {"scope", "name", "description", "overwrite"}
+//	GET  /v1/events                  server-sent "current" events
+//
+// scope may be omitted to use the first served scope. Failures are returned
+// as {"error": "..."} with a matching HTTP status.
+package server
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/client"
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+)
+
+// Scope pairs a scope name with the client that manages it.
+type Scope struct {
+	Name   string
+	Client *client.Client
+}
+
+// CurrentEvent is sent to /v1/events subscribers when the active context of
+// a scope changes.
+type CurrentEvent struct {
+	Scope    string `json:"scope"`
+	Name     string `json:"name"`
+	Previous string `json:"previous,omitempty"`
+}
+
+// Server serves the API for a set of scopes.
+type Server struct {
+	scopes []Scope
+	mux    *http.ServeMux
+	opMu   sync.Mutex // serializes switch and save
+
+	mu          sync.Mutex
+	current     map[string]string
+	subscribers map[chan CurrentEvent]struct{}
+}
+
+// New returns a server for scopes. The first scope is the default.
+func New(scopes []Scope) *Server {
+	s := &Server{
+		scopes:      scopes,
+		mux:         http.NewServeMux(),
+		current:     map[string]string{},
+		subscribers: map[chan CurrentEvent]struct{}{},
+	}
+	for _, sc := range scopes {
+		s.current[sc.Name], _ = sc.Client.Current(gocontext.Background())
+	}
+	s.mux.HandleFunc("GET /v1/scopes", s.handleScopes)
+	s.mux.HandleFunc("GET /v1/contexts", s.handleContexts)
+	s.mux.HandleFunc("GET /v1/current", s.handleCurrent)
+	s.mux.HandleFunc("GET /v1/status", s.handleStatus)
+	s.mux.HandleFunc("POST /v1/switch", s.handleSwitch)
+	s.mux.HandleFunc("POST /v1/save", s.handleSave)
+	s.mux.HandleFunc("GET /v1/events", s.handleEvents)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Watch polls the active context of every scope until ctx is done so that
+// switches made outside the server (e.g. by the CLI) reach subscribers too.
+func (s *Server) Watch(ctx gocontext.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+// refresh re-reads the active context of every scope and notifies
+// subscribers of changes.
+func (s *Server) refresh(ctx gocontext.Context) {
+	for _, sc := range s.scopes {
+		name, err := sc.Client.Current(ctx)
+		if err != nil {
+			continue
+		}
+		s.mu.Lock()
+		prev := s.current[sc.Name]
+		if name != prev {
+			s.current[sc.Name] = name
+			ev := CurrentEvent{Scope: sc.Name, Name: name, Previous: prev}
+			for ch := range s.subscribers {
+				select {
+				case ch <- ev:
+				default: // slow subscriber; it will see the next change
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) subscribe() chan CurrentEvent {
+	ch := make(chan CurrentEvent, 8)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan CurrentEvent) {
+	s.mu.Lock()
+	delete(s.subscribers, ch)
+	s.mu.Unlock()
+}
+
+// client returns the client for the scope named in the request, or the
+// default scope.
+func (s *Server) client(name string) (*client.Client, string, error) {
+	if len(s.scopes) == 0 {
+		return nil, "", fmt.Errorf("no scopes served")
+	}
+	if name == "" {
+		return s.scopes[0].Client, s.scopes[0].Name, nil
+	}
+	for _, sc := range s.scopes {
+		if sc.Name == name {
+			return sc.Client, sc.Name, nil
+		}
+	}
+	return nil, "", fmt.Errorf("scope %q is not served: %w", name, context.ErrNotFound)
+}
+
+func (s *Server) handleScopes(w http.ResponseWriter, r *http.Request) {
+	names := []string{}
+	for _, sc := range s.scopes {
+		names = append(names, sc.Name)
+	}
+	writeJSON(w, http.StatusOK, names)
+}
+
+func (s *Server) handleContexts(w http.ResponseWriter, r *http.Request) {
+	c, _, err := s.client(r.URL.Query().Get("scope"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	list, err := c.List(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleCurrent(w http.ResponseWriter, r *http.Request) {
+	c, scope, err := s.client(r.URL.Query().Get("scope"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	name, err := c.Current(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, CurrentEvent{Scope: scope, Name: name})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	c, _, err := s.client(r.URL.Query().Get("scope"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		if name, err = c.Current(r.Context()); err != nil {
+			writeError(w, err)
+			return
+		}
+		if name == "" {
+			writeError(w, fmt.Errorf("no active context: %w", context.ErrNotFound))
+			return
+		}
+	}
+	drift, err := c.Diff(r.Context(), name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, statusOutput{DriftResult: drift, Clean: drift.Clean()})
+}
+
+// statusOutput is the result of /v1/status.
+type statusOutput struct {
+	*context.DriftResult
+	Clean bool `json:"clean"`
+}
+
+type switchRequest struct {
+	Scope string `json:"scope"`
+	Name  string `json:"name"`
+	Force bool   `json:"force"`
+}
+
+func (s *Server) handleSwitch(w http.ResponseWriter, r *http.Request) {
+	var req switchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	c, _, err := s.client(req.Scope)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+	result, err := c.Switch(r.Context(), req.Name, client.SwitchOptions{Force: req.Force})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.refresh(r.Context())
+	writeJSON(w, http.StatusOK, result)
+}
+
+type saveRequest struct {
+	Scope       string `json:"scope"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Overwrite   bool   `json:"overwrite"`
+}
+
+func (s *Server) handleSave(w http.ResponseWriter, r *http.Request) {
+	var req saveRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	c, _, err := s.client(req.Scope)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+	result, err := c.Save(r.Context(), client.SaveOptions{
+		Name:        req.Name,
+		Description: req.Description,
+		Overwrite:   req.Overwrite,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.refresh(r.Context())
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, fmt.Errorf("streaming not supported"))
+		return
+	}
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			data, _ := json.Marshal(ev)
+			fmt.Fprintf(w, "event: current\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorOutput{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return false
+	}
+	return true
+}
+
+type errorOutput struct {
+	Error string `json:"error"`
+}
+
+// statusFor maps an error from a Client to an HTTP status.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, context.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.ErrExists),
+		errors.Is(err, context.ErrActive),
+		errors.Is(err, context.ErrScopeMismatch),
+		errors.Is(err, context.ErrUncommitted):
+		return http.StatusConflict
+	case errors.Is(err, context.ErrLocked):
+		return http.StatusLocked
+	case errors.Is(err, context.ErrInvalidName),
+		errors.Is(err, config.ErrInvalidScope):
+		return http.StatusBadRequest
+	case errors.Is(err, context.ErrInvalidFiles),
+		errors.Is(err, config.ErrInvalidConfig):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), errorOutput{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bufio"
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfldy2850/claudectx/pkg/claudectx"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	root := t.TempDir()
+	client, err := claudectx.New(claudectx.Options{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(New([]Scope{{Name: "project", Client: client}}))
+	t.Cleanup(srv.Close)
+	return srv, root
+}
+
+func post(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServerAPI(t *testing.T) {
+	srv, root := newTestServer(t)
+	claudeMD := filepath.Join(root, "CLAUDE.md")
+
+	os.WriteFile(claudeMD, []byte("# Work"), 0644)
+	if resp := post(t, srv.URL+"/v1/save", `{"name": "work"}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("save: status %d", resp.StatusCode)
+	}
+	if resp := post(t, srv.URL+"/v1/save", `{"name": "work"}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("save of an existing context: status %d, want 409", resp.StatusCode)
+	}
+
+	resp, err := http.Get(srv.URL + "/v1/contexts?scope=project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list []claudectx.Info
+	json.NewDecoder(resp.Body).Decode(&list)
+	resp.Body.Close()
+	if len(list) != 1 || list[0].Name != "work" || !list[0].Current {
+		t.Errorf("unexpected contexts: %+v", list)
+	}
+
+	os.WriteFile(claudeMD, []byte("# Edited"), 0644)
+	resp, err = http.Get(srv.URL + "/v1/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var status struct {
+		Name     string   `json:"name"`
+		Modified []string `json:"modified"`
+		Clean    bool     `json:"clean"`
+	}
+	json.NewDecoder(resp.Body).Decode(&status)
+	resp.Body.Close()
+	if status.Name != "work" || status.Clean || len(status.Modified) != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if resp := post(t, srv.URL+"/v1/switch", `{"name": "missing"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("switch to a missing context: status %d, want 404", resp.StatusCode)
+	}
+	resp, err = http.Get(srv.URL + "/v1/current?scope=user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unserved scope: status %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("context %q %w", "work", claudectx.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("context %q %w", "work", claudectx.ErrExists), http.StatusConflict},
+		{fmt.Errorf("%w: CLAUDE.md", claudectx.ErrUncommitted), http.StatusConflict},
+		{fmt.Errorf("context %q %w", "work", claudectx.ErrLocked), http.StatusLocked},
+		{fmt.Errorf("%w %q", claudectx.ErrInvalidScope, "nope"), http.StatusBadRequest},
+		{fmt.Errorf("%w /tmp/config.json: bad", claudectx.ErrInvalidConfig), http.StatusUnprocessableEntity},
+		{errors.New("disk full"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := statusFor(tt.err); got != tt.want {
+			t.Errorf("statusFor(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	srv, root := newTestServer(t)
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Work"), 0644)
+	post(t, srv.URL+"/v1/save", `{"name": "work"}`)
+
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/v1/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	post(t, srv.URL+"/v1/save", `{"name": "personal"}`)
+
+	scanner := bufio.NewScanner(resp.Body)
+	var event, data string
+	for scanner.Scan() && data == "" {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "event: "); ok {
+			event = v
+		}
+		if v, ok := strings.CutPrefix(line, "data: "); ok {
+			data = v
+		}
+	}
+	var ev CurrentEvent
+	json.Unmarshal([]byte(data), &ev)
+	if event != "current" || ev.Name != "personal" || ev.Previous != "work" {
+		t.Errorf("unexpected event %q: %+v", event, ev)
+	}
+}
@@ -0,0 +1,163 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed all:bundled
+var bundledFS embed.FS
+
+// ErrNotFound is wrapped by Find when no template has the requested name.
+var ErrNotFound = errors.New("not found")
+
+// metaFile is the optional per-template metadata file, not rendered into the context.
+const metaFile = "template.json"
+
+// Template is a directory of files rendered into a new context.
+// Its layout mirrors a context directory: extra files (CLAUDE.md, .mcp.json,
+// .claude.json) at the top level and .claude/ files under dotclaude/.
+type Template struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Scope       string `json:"scope,omitempty"` // restricts the template to a scope if set
+	Source      string `json:"source"`          // "bundled" or the user template directory
+	fsys        fs.FS
+}
+
+// Data holds the variables available to template files.
+type Data struct {
+	ContextName string
+	ProjectName string
+	ProjectRoot string
+	Scope       string
+	Vars        map[string]string // user-supplied --var key=value pairs
+}
+
+// List returns all available templates. Templates in userDirs (searched in
+// order) take precedence over bundled templates with the same name.
+func List(userDirs ...string) ([]Template, error) {
+	byName := map[string]Template{}
+
+	bundled, err := fs.Sub(bundledFS, "bundled")
+	if err != nil {
+		return nil, err
+	}
+	if err := collect(bundled, "bundled", byName); err != nil {
+		return nil, err
+	}
+
+	for i := len(userDirs) - 1; i >= 0; i-- {
+		dir := userDirs[i]
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := collect(os.DirFS(dir), dir, byName); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]Template, 0, len(names))
+	for _, name := range names {
+		result = append(result, byName[name])
+	}
+	return result, nil
+}
+
+// Find returns the template with the given name.
+func Find(name string, userDirs ...string) (*Template, error) {
+	all, err := List(userDirs...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].Name == name {
+			return &all[i], nil
+		}
+	}
+	return nil, fmt.Errorf("template %q %w", name, ErrNotFound)
+}
+
+func collect(root fs.FS, source string, byName map[string]Template) error {
+	entries, err := fs.ReadDir(root, ".")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		sub, err := fs.Sub(root, e.Name())
+		if err != nil {
+			return err
+		}
+		t := Template{Name: e.Name(), Source: source, fsys: sub}
+		if data, err := fs.ReadFile(sub, metaFile); err == nil {
+			if err := json.Unmarshal(data, &t); err != nil {
+				return fmt.Errorf("parse %s for template %q: %w", metaFile, e.Name(), err)
+			}
+			t.Name = e.Name()
+			t.Source = source
+		}
+		byName[t.Name] = t
+	}
+	return nil
+}
+
+// Render executes every file in the template with the given data and returns
+// the results keyed by their slash-separated path within the template.
+func (t *Template) Render(data Data) (map[string][]byte, error) {
+	funcs := template.FuncMap{
+		"env": os.Getenv,
+		"default": func(def, value string) string {
+			if value == "" {
+				return def
+			}
+			return value
+		},
+		"var": func(key string) string { return data.Vars[key] },
+	}
+
+	files := map[string][]byte{}
+	err := fs.WalkDir(t.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path == metaFile {
+			return nil
+		}
+		raw, err := fs.ReadFile(t.fsys, path)
+		if err != nil {
+			return err
+		}
+		tpl, err := template.New(path).Funcs(funcs).Option("missingkey=error").Parse(string(raw))
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("render %s: %w", path, err)
+		}
+		files[filepath.ToSlash(path)] = buf.Bytes()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", t.Name, err)
+	}
+	return files, nil
+}
@@ -0,0 +1,114 @@
+package templates
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListBundled(t *testing.T) {
+	all, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 {
+		t.Fatal("expected bundled templates")
+	}
+	for _, tpl := range all {
+		if tpl.Source != "bundled" {
+			t.Errorf("expected bundled source for %s, got %s", tpl.Name, tpl.Source)
+		}
+		if tpl.Scope == "" {
+			t.Errorf("expected bundled template %s to declare a scope", tpl.Name)
+		}
+	}
+}
+
+func TestBundledTemplatesRender(t *testing.T) {
+	all, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tpl := range all {
+		t.Run(tpl.Name, func(t *testing.T) {
+			files, err := tpl.Render(Data{ContextName: "ctx", ProjectName: "demo", Scope: tpl.Scope})
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			if _, ok := files[metaFile]; ok {
+				t.Error("expected template.json to be excluded")
+			}
+			for path, data := range files {
+				if strings.HasSuffix(path, ".json") && !json.Valid(data) {
+					t.Errorf("%s rendered to invalid JSON:\n%s", path, data)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderVariablesAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	tplDir := filepath.Join(dir, "custom")
+	os.MkdirAll(filepath.Join(tplDir, "dotclaude"), 0755)
+	os.WriteFile(filepath.Join(tplDir, "template.json"), []byte(`{"description":"Custom","scope":"project"}`), 0644)
+	os.WriteFile(filepath.Join(tplDir, "CLAUDE.md"), []byte(`# {{.ProjectName}} ({{.ContextName}})`), 0644)
+	os.WriteFile(filepath.Join(tplDir, "dotclaude", "settings.json"),
+		[]byte(`{"api":"{{env "CLAUDECTX_TEST_API"}}","team":"{{var "team"}}"}`), 0644)
+	t.Setenv("CLAUDECTX_TEST_API", "https://api.example.com")
+
+	tpl, err := Find("custom", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpl.Description != "Custom" || tpl.Source != dir {
+		t.Errorf("unexpected template metadata: %+v", tpl)
+	}
+
+	files, err := tpl.Render(Data{ContextName: "work", ProjectName: "acme", Vars: map[string]string{"team": "infra"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(files["CLAUDE.md"]); got != "# acme (work)" {
+		t.Errorf("got CLAUDE.md %q", got)
+	}
+	if got := string(files["dotclaude/settings.json"]); got != `{"api":"https://api.example.com","team":"infra"}` {
+		t.Errorf("got settings.json %q", got)
+	}
+}
+
+func TestUserTemplateOverridesBundled(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "project-basic"), 0755)
+	os.WriteFile(filepath.Join(dir, "project-basic", "CLAUDE.md"), []byte("override"), 0644)
+
+	tpl, err := Find("project-basic", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpl.Source != dir {
+		t.Errorf("expected user template to win, got source %s", tpl.Source)
+	}
+}
+
+func TestRenderMissingKeyErrors(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "bad"), 0755)
+	os.WriteFile(filepath.Join(dir, "bad", "CLAUDE.md"), []byte(`{{.Nope}}`), 0644)
+
+	tpl, err := Find("bad", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tpl.Render(Data{}); err == nil {
+		t.Fatal("expected error for unknown variable")
+	}
+}
+
+func TestFindUnknown(t *testing.T) {
+	if _, err := Find("does-not-exist"); err == nil {
+		t.Fatal("expected error for unknown template")
+	}
+}
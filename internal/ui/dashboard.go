@@ -0,0 +1,540 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DashboardBackend supplies data to the dashboard and performs its actions.
+// The CLI implements it on top of the context package; tests use a fake.
+type DashboardBackend interface {
+	Scopes() []string
+	Contexts(scope string) ([]PickerItem, error)
+	Backups(scope string) ([]BackupItem, error)
+	ReadFile(scope string, src FileSource, relPath string) ([]byte, error)
+	Drift(scope, name string) (*DriftView, error)
+	Save(scope, name string, overwrite bool) error
+	Switch(scope, name string) error
+	Delete(scope, name string) error
+}
+
+// BackupItem is a pre-switch backup of the live files.
+type BackupItem struct {
+	Name      string
+	CreatedAt time.Time
+	Paths     []string
+	TotalSize int64
+}
+
+// FileSource identifies where a viewed file lives: a saved context or a backup.
+type FileSource struct {
+	Backup bool
+	Name   string
+}
+
+// DriftView lists differences between the live files and a saved context.
+type DriftView struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+type dashView int
+
+const (
+	viewContexts dashView = iota
+	viewFiles
+	viewContent
+	viewDrift
+	viewBackups
+)
+
+type dashPrompt int
+
+const (
+	promptNone dashPrompt = iota
+	promptDelete
+	promptOverwrite
+	promptNewName
+)
+
+type dashboardModel struct {
+	backend DashboardBackend
+	scopes  []string
+	scope   int
+	view    dashView
+
+	contexts []PickerItem
+	cursor   int
+
+	backups      []BackupItem
+	backupCursor int
+
+	files       []string
+	fileCursor  int
+	fileSource  FileSource
+	filesParent dashView
+
+	content      []string
+	contentTitle string
+	scroll       int
+
+	drift      *DriftView
+	driftTitle string
+
+	prompt dashPrompt
+	input  string
+	status string
+
+	width  int
+	height int
+}
+
+func newDashboardModel(backend DashboardBackend) dashboardModel {
+	m := dashboardModel{backend: backend, scopes: backend.Scopes()}
+	m.reload()
+	return m
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *dashboardModel) currentScope() string {
+	if len(m.scopes) == 0 {
+		return ""
+	}
+	return m.scopes[m.scope]
+}
+
+func (m *dashboardModel) selectedContext() (PickerItem, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.contexts) {
+		return PickerItem{}, false
+	}
+	return m.contexts[m.cursor], true
+}
+
+// reload refreshes the context list of the active scope.
+func (m *dashboardModel) reload() {
+	items, err := m.backend.Contexts(m.currentScope())
+	if err != nil {
+		m.status = "Error: " + err.Error()
+		return
+	}
+	m.contexts = items
+	m.cursor = clamp(m.cursor, len(m.contexts))
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.prompt != promptNone {
+			return m.updatePrompt(msg)
+		}
+		if msg.String() == "q" {
+			return m, tea.Quit
+		}
+		switch m.view {
+		case viewContexts:
+			return m.updateContexts(msg)
+		case viewFiles:
+			return m.updateFiles(msg)
+		case viewContent:
+			return m.updateContent(msg)
+		case viewBackups:
+			return m.updateBackups(msg)
+		case viewDrift:
+			if msg.Type == tea.KeyEsc {
+				m.view = viewContexts
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m dashboardModel) updateContexts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+	item, ok := m.selectedContext()
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.contexts)-1 {
+			m.cursor++
+		}
+	case "tab", "shift+tab":
+		if len(m.scopes) > 1 {
+			step := 1
+			if msg.Type == tea.KeyShiftTab {
+				step = len(m.scopes) - 1
+			}
+			m.scope = (m.scope + step) % len(m.scopes)
+			m.cursor = 0
+			m.reload()
+		}
+	case "enter":
+		if ok {
+			m.runAction(m.backend.Switch(m.currentScope(), item.Name), fmt.Sprintf("Switched to %q", item.Name))
+		}
+	case "f":
+		if ok {
+			m.openFiles(FileSource{Name: item.Name}, item.Paths, viewContexts)
+		}
+	case "d":
+		if ok {
+			drift, err := m.backend.Drift(m.currentScope(), item.Name)
+			if err != nil {
+				m.status = "Error: " + err.Error()
+				break
+			}
+			m.drift, m.driftTitle = drift, item.Name
+			m.view = viewDrift
+		}
+	case "b":
+		backups, err := m.backend.Backups(m.currentScope())
+		if err != nil {
+			m.status = "Error: " + err.Error()
+			break
+		}
+		m.backups, m.backupCursor = backups, 0
+		m.view = viewBackups
+	case "s":
+		if ok {
+			m.prompt = promptOverwrite
+		}
+	case "n":
+		m.prompt = promptNewName
+		m.input = ""
+	case "x":
+		switch {
+		case !ok:
+		case item.IsCurrent:
+			m.status = "Cannot delete the active context"
+		case item.Locked:
+			m.status = fmt.Sprintf("Context %q is locked", item.Name)
+		default:
+			m.prompt = promptDelete
+		}
+	case "r":
+		m.reload()
+	}
+	return m, nil
+}
+
+func (m dashboardModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := m.prompt
+	item, ok := m.selectedContext()
+
+	if prompt == promptNewName {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.prompt, m.input = promptNone, ""
+		case tea.KeyBackspace:
+			if m.input != "" {
+				r := []rune(m.input)
+				m.input = string(r[:len(r)-1])
+			}
+		case tea.KeyRunes:
+			m.input += string(msg.Runes)
+		case tea.KeyEnter:
+			name := strings.TrimSpace(m.input)
+			m.prompt, m.input = promptNone, ""
+			if name != "" {
+				m.runAction(m.backend.Save(m.currentScope(), name, false), fmt.Sprintf("Saved live files as %q", name))
+			}
+		}
+		return m, nil
+	}
+
+	m.prompt = promptNone
+	if !ok || msg.String() != "y" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	switch prompt {
+	case promptDelete:
+		m.runAction(m.backend.Delete(m.currentScope(), item.Name), fmt.Sprintf("Deleted %q", item.Name))
+	case promptOverwrite:
+		m.runAction(m.backend.Save(m.currentScope(), item.Name, true), fmt.Sprintf("Saved live files into %q", item.Name))
+	}
+	return m, nil
+}
+
+// runAction reports the outcome of a backend action and refreshes the list.
+func (m *dashboardModel) runAction(err error, done string) {
+	if err != nil {
+		m.status = "Error: " + err.Error()
+		return
+	}
+	m.reload()
+	m.status = done
+}
+
+func (m *dashboardModel) openFiles(src FileSource, paths []string, parent dashView) {
+	m.fileSource = src
+	m.files = paths
+	m.fileCursor = 0
+	m.filesParent = parent
+	m.view = viewFiles
+}
+
+func (m dashboardModel) updateFiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = m.filesParent
+	case "up", "k":
+		if m.fileCursor > 0 {
+			m.fileCursor--
+		}
+	case "down", "j":
+		if m.fileCursor < len(m.files)-1 {
+			m.fileCursor++
+		}
+	case "enter":
+		if m.fileCursor >= len(m.files) {
+			break
+		}
+		relPath := m.files[m.fileCursor]
+		data, err := m.backend.ReadFile(m.currentScope(), m.fileSource, relPath)
+		if err != nil {
+			m.status = "Error: " + err.Error()
+			break
+		}
+		m.content = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		m.contentTitle = m.fileSource.Name + "/" + relPath
+		m.scroll = 0
+		m.view = viewContent
+	}
+	return m, nil
+}
+
+func (m dashboardModel) updateContent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := m.bodyRows()
+	last := max(len(m.content)-page, 0)
+	switch msg.String() {
+	case "esc":
+		m.view = viewFiles
+	case "up", "k":
+		m.scroll = max(m.scroll-1, 0)
+	case "down", "j":
+		m.scroll = min(m.scroll+1, last)
+	case "pgup":
+		m.scroll = max(m.scroll-page, 0)
+	case "pgdown", " ":
+		m.scroll = min(m.scroll+page, last)
+	}
+	return m, nil
+}
+
+func (m dashboardModel) updateBackups(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = viewContexts
+	case "up", "k":
+		if m.backupCursor > 0 {
+			m.backupCursor--
+		}
+	case "down", "j":
+		if m.backupCursor < len(m.backups)-1 {
+			m.backupCursor++
+		}
+	case "enter":
+		if m.backupCursor < len(m.backups) {
+			b := m.backups[m.backupCursor]
+			m.openFiles(FileSource{Backup: true, Name: b.Name}, b.Paths, viewBackups)
+		}
+	}
+	return m, nil
+}
+
+var (
+	headerStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	changedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// dashChromeLines is the number of lines used by the header, status and help.
+const dashChromeLines = 5
+
+func (m dashboardModel) bodyRows() int {
+	height := m.height
+	if height == 0 {
+		height = defaultPickerHeight
+	}
+	return max(height-dashChromeLines, 3)
+}
+
+func (m dashboardModel) View() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render("claudectx"))
+	for i, s := range m.scopes {
+		b.WriteString("  ")
+		if i == m.scope {
+			b.WriteString(activeTab.Render(s))
+		} else {
+			b.WriteString(dimStyle.Render(s))
+		}
+	}
+	b.WriteString("\n\n")
+
+	var body, help string
+	switch m.view {
+	case viewContexts:
+		body = m.viewContextList()
+		help = "enter switch • f files • d drift • b backups • s save live • n save as new • x delete • r refresh • q quit"
+		if len(m.scopes) > 1 {
+			help = "tab scope • " + help
+		}
+	case viewFiles:
+		body = m.viewFileList()
+		help = "enter view • esc back • q quit"
+	case viewContent:
+		body = m.viewFileContent()
+		help = "↑/↓ scroll • pgup/pgdown page • esc back • q quit"
+	case viewDrift:
+		body = m.viewDriftReport()
+		help = "esc back • q quit"
+	case viewBackups:
+		body = m.viewBackupList()
+		help = "enter files • esc back • q quit"
+	}
+	b.WriteString(body)
+	b.WriteString("\n\n")
+
+	switch m.prompt {
+	case promptDelete:
+		item, _ := m.selectedContext()
+		b.WriteString(fmt.Sprintf("Delete context %q? (y/N)", item.Name))
+	case promptOverwrite:
+		item, _ := m.selectedContext()
+		b.WriteString(fmt.Sprintf("Overwrite %q with the live files? (y/N)", item.Name))
+	case promptNewName:
+		b.WriteString("Save live files as: " + m.input)
+	default:
+		if m.status != "" {
+			b.WriteString(m.status + "\n")
+		}
+		b.WriteString(dimStyle.Render(help))
+	}
+	return b.String()
+}
+
+func (m dashboardModel) viewContextList() string {
+	if len(m.contexts) == 0 {
+		return dimStyle.Render("No saved contexts. Press n to save the live files.")
+	}
+	rows := m.bodyRows()
+	start := 0
+	if m.cursor >= rows {
+		start = m.cursor - rows + 1
+	}
+	var lines []string
+	for i := start; i < min(start+rows, len(m.contexts)); i++ {
+		item := m.contexts[i]
+		cursor, style := "  ", normalStyle
+		if i == m.cursor {
+			cursor, style = "> ", selectedStyle
+		}
+		line := cursor + style.Render(item.Name)
+		if item.IsCurrent {
+			line += currentStyle.Render(" (active)")
+		}
+		if item.Locked {
+			line += dimStyle.Render(" [locked]")
+		}
+		line += dimStyle.Render(fmt.Sprintf("  %d files, %s, updated %s",
+			item.Files, formatSizeUI(item.TotalSize), item.UpdatedAt.Format("2006-01-02 15:04")))
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m dashboardModel) viewFileList() string {
+	title := "Files in " + m.fileSource.Name
+	if len(m.files) == 0 {
+		return titleStyle.Render(title) + "\n" + dimStyle.Render("  (empty)")
+	}
+	lines := []string{titleStyle.Render(title)}
+	rows := m.bodyRows() - 1
+	start := 0
+	if m.fileCursor >= rows {
+		start = m.fileCursor - rows + 1
+	}
+	for i := start; i < min(start+rows, len(m.files)); i++ {
+		if i == m.fileCursor {
+			lines = append(lines, "> "+selectedStyle.Render(m.files[i]))
+		} else {
+			lines = append(lines, "  "+normalStyle.Render(m.files[i]))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m dashboardModel) viewFileContent() string {
+	rows := m.bodyRows() - 1
+	end := min(m.scroll+rows, len(m.content))
+	lines := []string{titleStyle.Render(m.contentTitle)}
+	lines = append(lines, m.content[m.scroll:end]...)
+	return strings.Join(lines, "\n")
+}
+
+func (m dashboardModel) viewDriftReport() string {
+	lines := []string{titleStyle.Render("Live files vs " + m.driftTitle)}
+	d := m.drift
+	if d == nil || (len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0) {
+		return lines[0] + "\n" + dimStyle.Render("  No differences.")
+	}
+	for _, p := range d.Added {
+		lines = append(lines, addedStyle.Render("  + "+p))
+	}
+	for _, p := range d.Removed {
+		lines = append(lines, removedStyle.Render("  - "+p))
+	}
+	for _, p := range d.Modified {
+		lines = append(lines, changedStyle.Render("  ~ "+p))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m dashboardModel) viewBackupList() string {
+	if len(m.backups) == 0 {
+		return dimStyle.Render("No backups.")
+	}
+	lines := []string{titleStyle.Render("Backups")}
+	for i, bk := range m.backups {
+		cursor, style := "  ", normalStyle
+		if i == m.backupCursor {
+			cursor, style = "> ", selectedStyle
+		}
+		lines = append(lines, cursor+style.Render(bk.Name)+dimStyle.Render(fmt.Sprintf("  %d files, %s",
+			len(bk.Paths), formatSizeUI(bk.TotalSize))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clamp keeps a cursor within a list of n items.
+func clamp(cursor, n int) int {
+	if cursor >= n {
+		cursor = n - 1
+	}
+	return max(cursor, 0)
+}
+
+// RunDashboard runs the full-screen management dashboard until the user quits.
+func RunDashboard(backend DashboardBackend) error {
+	p := tea.NewProgram(newDashboardModel(backend), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeBackend is an in-memory DashboardBackend that records actions.
+type fakeBackend struct {
+	contexts map[string][]PickerItem
+	files    map[string]string // "<source>/<path>" -> content
+	calls    []string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		contexts: map[string][]PickerItem{
+			"project": {
+				{Name: "backend", IsCurrent: true, Paths: []string{"CLAUDE.md"}},
+				{Name: "frontend", Paths: []string{"CLAUDE.md", "dotclaude/settings.json"}},
+			},
+			"user": {{Name: "personal"}},
+		},
+		files: map[string]string{
+			"frontend/dotclaude/settings.json":              "{\n  \"model\": \"opus\"\n}\n",
+			"pre-switch-20250101-100000/CLAUDE.md":          "# Backup",
+			"pre-switch-20250101-100000/dotclaude/foo.json": "{}",
+		},
+	}
+}
+
+func (f *fakeBackend) Scopes() []string { return []string{"project", "user"} }
+
+func (f *fakeBackend) Contexts(scope string) ([]PickerItem, error) {
+	return f.contexts[scope], nil
+}
+
+func (f *fakeBackend) Backups(scope string) ([]BackupItem, error) {
+	return []BackupItem{{Name: "pre-switch-20250101-100000", Paths: []string{"CLAUDE.md", "dotclaude/foo.json"}}}, nil
+}
+
+func (f *fakeBackend) ReadFile(scope string, src FileSource, relPath string) ([]byte, error) {
+	data, ok := f.files[src.Name+"/"+relPath]
+	if !ok {
+		return nil, errors.New("no such file")
+	}
+	return []byte(data), nil
+}
+
+func (f *fakeBackend) Drift(scope, name string) (*DriftView, error) {
+	return &DriftView{Added: []string{"dotclaude/new.md"}, Modified: []string{"CLAUDE.md"}}, nil
+}
+
+func (f *fakeBackend) Save(scope, name string, overwrite bool) error {
+	f.calls = append(f.calls, "save "+scope+"/"+name)
+	if !overwrite {
+		f.contexts[scope] = append(f.contexts[scope], PickerItem{Name: name})
+	}
+	return nil
+}
+
+func (f *fakeBackend) Switch(scope, name string) error {
+	f.calls = append(f.calls, "switch "+scope+"/"+name)
+	return nil
+}
+
+func (f *fakeBackend) Delete(scope, name string) error {
+	f.calls = append(f.calls, "delete "+scope+"/"+name)
+	var kept []PickerItem
+	for _, item := range f.contexts[scope] {
+		if item.Name != name {
+			kept = append(kept, item)
+		}
+	}
+	f.contexts[scope] = kept
+	return nil
+}
+
+func drive(m dashboardModel, msgs ...tea.Msg) dashboardModel {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(dashboardModel)
+	}
+	return m
+}
+
+func TestDashboardBrowseFiles(t *testing.T) {
+	m := newDashboardModel(newFakeBackend())
+
+	m = drive(m, runes("j"), runes("f"))
+	if m.view != viewFiles || len(m.files) != 2 {
+		t.Fatalf("expected files view of frontend, got view %d with %v", m.view, m.files)
+	}
+
+	m = drive(m, runes("j"), key(tea.KeyEnter))
+	if m.view != viewContent {
+		t.Fatalf("expected content view, status %q", m.status)
+	}
+	if view := m.View(); !strings.Contains(view, `"model": "opus"`) || !strings.Contains(view, "frontend/dotclaude/settings.json") {
+		t.Errorf("expected file content in view, got:\n%s", view)
+	}
+
+	m = drive(m, key(tea.KeyEsc), key(tea.KeyEsc))
+	if m.view != viewContexts {
+		t.Errorf("expected esc to return to contexts, got view %d", m.view)
+	}
+}
+
+func TestDashboardDriftAndBackups(t *testing.T) {
+	m := newDashboardModel(newFakeBackend())
+
+	m = drive(m, runes("d"))
+	view := m.View()
+	if m.view != viewDrift || !strings.Contains(view, "+ dotclaude/new.md") || !strings.Contains(view, "~ CLAUDE.md") {
+		t.Errorf("expected drift report, got:\n%s", view)
+	}
+
+	m = drive(m, key(tea.KeyEsc), runes("b"), key(tea.KeyEnter), key(tea.KeyEnter))
+	if m.view != viewContent || m.content[0] != "# Backup" {
+		t.Errorf("expected backup file content, got view %d, %v (status %q)", m.view, m.content, m.status)
+	}
+	m = drive(m, key(tea.KeyEsc), key(tea.KeyEsc))
+	if m.view != viewBackups {
+		t.Errorf("expected esc from backup files to return to backups, got view %d", m.view)
+	}
+}
+
+func TestDashboardActions(t *testing.T) {
+	backend := newFakeBackend()
+	m := newDashboardModel(backend)
+
+	// Active context cannot be deleted.
+	m = drive(m, runes("x"))
+	if m.prompt != promptNone || !strings.Contains(m.status, "active") {
+		t.Fatalf("expected active context to be protected, status %q", m.status)
+	}
+
+	m = drive(m, runes("j"), runes("x"), runes("y"))
+	m = drive(m, runes("s"), runes("y"))
+	m = drive(m, runes("n"), runes("scratch"), key(tea.KeyEnter))
+	m = drive(m, key(tea.KeyTab), key(tea.KeyEnter))
+
+	want := []string{"delete project/frontend", "save project/backend", "save project/scratch", "switch user/personal"}
+	if strings.Join(backend.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", backend.calls, want)
+	}
+	if m.currentScope() != "user" || !strings.Contains(m.status, "Switched") {
+		t.Errorf("unexpected state after switch: scope %q, status %q", m.currentScope(), m.status)
+	}
+}
+
+func TestDashboardPromptCancel(t *testing.T) {
+	backend := newFakeBackend()
+	m := newDashboardModel(backend)
+
+	m = drive(m, runes("s"), runes("n"), runes("n"), key(tea.KeyEsc))
+	if len(backend.calls) != 0 || m.prompt != promptNone {
+		t.Errorf("expected cancelled prompts to do nothing, got %v", backend.calls)
+	}
+
+	next, cmd := m.Update(runes("q"))
+	if cmd == nil || next.(dashboardModel).prompt != promptNone {
+		t.Error("expected q to quit")
+	}
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore reports whether every rune of query appears in target in order
+// (case-insensitive) and scores the match. Consecutive runs, matches at the
+// start of a word and an exact prefix score higher.
+func fuzzyScore(query, target string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+
+	score := 0
+	qi := 0
+	prev := -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 3 // consecutive run
+		}
+		if ti == 0 || isWordBoundary(t[ti-1]) {
+			score += 2 // start of a word
+		}
+		prev = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	if strings.HasPrefix(string(t), string(q)) {
+		score += 5
+	}
+	return score, true
+}
+
+func isWordBoundary(r rune) bool {
+	return r == '-' || r == '_' || r == '.' || r == '/' || unicode.IsSpace(r)
+}
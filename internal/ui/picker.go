@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,97 +18,404 @@ type PickerItem struct {
 	TotalSize   int64
 	UpdatedAt   time.Time
 	IsCurrent   bool
+	Locked      bool
+	OAuthEmail  string
+	Paths       []string // stored file paths, shown in the preview pane
 }
 
+// PickerTab is one scope's list of contexts.
+type PickerTab struct {
+	Scope string // "project" or "user"
+	Items []PickerItem
+}
+
+// PickerActions performs context operations from inside the picker. Each
+// action returns the refreshed items of the affected scope. A nil action
+// disables its key binding.
+type PickerActions struct {
+	Delete    func(scope, name string) ([]PickerItem, error)
+	Rename    func(scope, oldName, newName string) ([]PickerItem, error)
+	Duplicate func(scope, name, newName string) ([]PickerItem, error)
+}
+
+// PickerSelection is the context chosen in the picker.
+type PickerSelection struct {
+	Scope string
+	Name  string
+}
+
+type pickerMode int
+
+const (
+	modeBrowse pickerMode = iota
+	modeConfirmDelete
+	modeRename
+	modeDuplicate
+)
+
 type pickerModel struct {
-	items    []PickerItem
+	tabs     []PickerTab
+	tab      int
+	query    string
+	filtered []int // indices into the active tab's items, best match first
 	cursor   int
-	selected string
+	mode     pickerMode
+	input    string // new name typed for rename/duplicate
+	status   string
+	actions  PickerActions
+	width    int
+	height   int
+	selected *PickerSelection
 	quitting bool
 }
 
+func newPickerModel(tabs []PickerTab, active int, actions PickerActions) pickerModel {
+	m := pickerModel{tabs: tabs, tab: active, actions: actions}
+	m.applyFilter()
+	return m
+}
+
 func (m pickerModel) Init() tea.Cmd {
 	return nil
 }
 
+// items returns the active tab's items.
+func (m *pickerModel) items() []PickerItem {
+	if len(m.tabs) == 0 {
+		return nil
+	}
+	return m.tabs[m.tab].Items
+}
+
+// current returns the item under the cursor, if any.
+func (m *pickerModel) current() (PickerItem, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) {
+		return PickerItem{}, false
+	}
+	return m.items()[m.filtered[m.cursor]], true
+}
+
+// applyFilter recomputes the visible items for the query, ranking fuzzy
+// matches on the name above matches on the description.
+func (m *pickerModel) applyFilter() {
+	type scored struct{ idx, score int }
+	var matches []scored
+	for i, item := range m.items() {
+		if s, ok := fuzzyScore(m.query, item.Name); ok {
+			matches = append(matches, scored{i, s + 100})
+		} else if s, ok := fuzzyScore(m.query, item.Description); ok {
+			matches = append(matches, scored{i, s})
+		}
+	}
+	if m.query != "" {
+		sort.SliceStable(matches, func(a, b int) bool { return matches[a].score > matches[b].score })
+	}
+	m.filtered = make([]int, 0, len(matches))
+	for _, s := range matches {
+		m.filtered = append(m.filtered, s.idx)
+	}
+	if m.cursor >= len(m.filtered) {
+		m.cursor = len(m.filtered) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
 func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "ctrl+c":
+		if msg.Type == tea.KeyCtrlC {
 			m.quitting = true
 			return m, tea.Quit
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down", "j":
-			if m.cursor < len(m.items)-1 {
-				m.cursor++
+		}
+		switch m.mode {
+		case modeConfirmDelete:
+			return m.updateConfirmDelete(msg)
+		case modeRename, modeDuplicate:
+			return m.updateNameInput(msg)
+		}
+		return m.updateBrowse(msg)
+	}
+	return m, nil
+}
+
+func (m pickerModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.query != "" {
+			m.query = ""
+			m.applyFilter()
+			return m, nil
+		}
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+		}
+	case tea.KeyTab, tea.KeyShiftTab:
+		if len(m.tabs) > 1 {
+			step := 1
+			if msg.Type == tea.KeyShiftTab {
+				step = len(m.tabs) - 1
 			}
-		case "enter":
-			m.selected = m.items[m.cursor].Name
+			m.tab = (m.tab + step) % len(m.tabs)
+			m.cursor = 0
+			m.status = ""
+			m.applyFilter()
+		}
+	case tea.KeyEnter:
+		if item, ok := m.current(); ok {
+			m.selected = &PickerSelection{Scope: m.tabs[m.tab].Scope, Name: item.Name}
 			return m, tea.Quit
 		}
+	case tea.KeyCtrlD:
+		item, ok := m.current()
+		switch {
+		case !ok || m.actions.Delete == nil:
+		case item.IsCurrent:
+			m.status = "Cannot delete the active context"
+		case item.Locked:
+			m.status = fmt.Sprintf("Context %q is locked", item.Name)
+		default:
+			m.mode = modeConfirmDelete
+		}
+	case tea.KeyCtrlR:
+		if item, ok := m.current(); ok && m.actions.Rename != nil {
+			m.mode = modeRename
+			m.input = item.Name
+		}
+	case tea.KeyCtrlY:
+		if item, ok := m.current(); ok && m.actions.Duplicate != nil {
+			m.mode = modeDuplicate
+			m.input = item.Name + "-copy"
+		}
+	case tea.KeyBackspace:
+		if m.query != "" {
+			r := []rune(m.query)
+			m.query = string(r[:len(r)-1])
+			m.applyFilter()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+		m.cursor = 0
+		m.applyFilter()
+	}
+	return m, nil
+}
+
+func (m pickerModel) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = modeBrowse
+	item, ok := m.current()
+	if !ok || msg.String() != "y" {
+		m.status = "Delete cancelled"
+		return m, nil
+	}
+	items, err := m.actions.Delete(m.tabs[m.tab].Scope, item.Name)
+	m.finishAction(items, err, fmt.Sprintf("Deleted %q", item.Name))
+	return m, nil
+}
+
+func (m pickerModel) updateNameInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeBrowse
+		m.input = ""
+	case tea.KeyBackspace:
+		if m.input != "" {
+			r := []rune(m.input)
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes:
+		m.input += string(msg.Runes)
+	case tea.KeyEnter:
+		item, ok := m.current()
+		mode, newName := m.mode, strings.TrimSpace(m.input)
+		m.mode = modeBrowse
+		m.input = ""
+		if !ok || newName == "" || newName == item.Name {
+			return m, nil
+		}
+		scope := m.tabs[m.tab].Scope
+		if mode == modeRename {
+			items, err := m.actions.Rename(scope, item.Name, newName)
+			m.finishAction(items, err, fmt.Sprintf("Renamed %q to %q", item.Name, newName))
+		} else {
+			items, err := m.actions.Duplicate(scope, item.Name, newName)
+			m.finishAction(items, err, fmt.Sprintf("Duplicated %q as %q", item.Name, newName))
+		}
 	}
 	return m, nil
 }
 
+// finishAction replaces the active tab's items after an action and sets the
+// status line.
+func (m *pickerModel) finishAction(items []PickerItem, err error, done string) {
+	if err != nil {
+		m.status = "Error: " + err.Error()
+		return
+	}
+	m.tabs[m.tab].Items = items
+	m.applyFilter()
+	m.status = done
+}
+
 var (
 	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
 	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170")).Bold(true)
 	normalStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
 	dimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 	currentStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	activeTab     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Underline(true)
+	previewStyle  = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).
+			BorderForeground(lipgloss.Color("240")).PaddingLeft(1)
+)
+
+// Layout defaults used before the terminal reports its size.
+const (
+	defaultPickerWidth  = 100
+	defaultPickerHeight = 24
+	pickerChromeLines   = 6 // title, filter, blank lines, status and help
 )
 
 func (m pickerModel) View() string {
-	if m.quitting && m.selected == "" {
+	if m.quitting && m.selected == nil {
 		return ""
 	}
 
-	s := titleStyle.Render("Select a context:") + "\n\n"
+	width, height := m.width, m.height
+	if width == 0 {
+		width = defaultPickerWidth
+	}
+	if height == 0 {
+		height = defaultPickerHeight
+	}
+	rows := max(height-pickerChromeLines, 3)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Select a context:"))
+	if len(m.tabs) > 1 {
+		b.WriteString("  ")
+		for i, t := range m.tabs {
+			label := fmt.Sprintf("%s (%d)", t.Scope, len(t.Items))
+			if i == m.tab {
+				b.WriteString(activeTab.Render(label))
+			} else {
+				b.WriteString(dimStyle.Render(label))
+			}
+			b.WriteString("  ")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("filter: ") + m.query + "\n\n")
+
+	listWidth := max(width*2/5, 30)
+	list := lipgloss.NewStyle().Width(listWidth).Render(m.viewList(rows))
+	preview := previewStyle.Width(max(width-listWidth-3, 20)).Render(m.viewPreview(rows))
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, preview))
+	b.WriteString("\n\n")
 
-	for i, item := range m.items {
+	switch m.mode {
+	case modeConfirmDelete:
+		item, _ := m.current()
+		b.WriteString(fmt.Sprintf("Delete context %q? (y/N)", item.Name))
+	case modeRename:
+		b.WriteString("Rename to: " + m.input)
+	case modeDuplicate:
+		b.WriteString("Duplicate as: " + m.input)
+	default:
+		if m.status != "" {
+			b.WriteString(m.status + "\n")
+		}
+		help := "type to filter • ↑/↓ navigate • enter select • ctrl+d delete • ctrl+r rename • ctrl+y duplicate • esc quit"
+		if len(m.tabs) > 1 {
+			help = "tab switch scope • " + help
+		}
+		b.WriteString(dimStyle.Render(help))
+	}
+	return b.String()
+}
+
+// viewList renders the filtered items, scrolled so the cursor stays visible.
+func (m pickerModel) viewList(rows int) string {
+	if len(m.filtered) == 0 {
+		return dimStyle.Render("  no matching contexts")
+	}
+	start := 0
+	if m.cursor >= rows {
+		start = m.cursor - rows + 1
+	}
+	end := min(start+rows, len(m.filtered))
+
+	var lines []string
+	for i := start; i < end; i++ {
+		item := m.items()[m.filtered[i]]
 		cursor := "  "
 		style := normalStyle
 		if i == m.cursor {
 			cursor = "> "
 			style = selectedStyle
 		}
-
-		name := item.Name
+		line := cursor + style.Render(item.Name)
 		if item.IsCurrent {
-			name += currentStyle.Render(" (active)")
+			line += currentStyle.Render(" (active)")
 		}
-
-		line := fmt.Sprintf("%s%s", cursor, style.Render(name))
-		detail := dimStyle.Render(fmt.Sprintf("    %d files, %s, updated %s",
-			item.Files, formatSizeUI(item.TotalSize), item.UpdatedAt.Format("2006-01-02")))
-
-		if item.Description != "" {
-			detail += dimStyle.Render(fmt.Sprintf("\n    %s", item.Description))
+		if item.Locked {
+			line += dimStyle.Render(" [locked]")
 		}
-
-		s += line + "\n" + detail + "\n\n"
+		lines = append(lines, line)
 	}
+	return strings.Join(lines, "\n")
+}
 
-	s += dimStyle.Render("↑/↓ navigate • enter select • q quit")
+// viewPreview renders details of the item under the cursor.
+func (m pickerModel) viewPreview(rows int) string {
+	item, ok := m.current()
+	if !ok {
+		return ""
+	}
+	lines := []string{titleStyle.Render(item.Name)}
+	if item.Description != "" {
+		lines = append(lines, item.Description)
+	}
+	if item.OAuthEmail != "" {
+		lines = append(lines, dimStyle.Render("OAuth: ")+item.OAuthEmail)
+	}
+	lines = append(lines, dimStyle.Render(fmt.Sprintf("%d files, %s, updated %s",
+		item.Files, formatSizeUI(item.TotalSize), item.UpdatedAt.Format("2006-01-02 15:04"))))
+	lines = append(lines, "")
 
-	return s
+	room := rows - len(lines)
+	for i, p := range item.Paths {
+		if i == room-1 && len(item.Paths) > room {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("… %d more", len(item.Paths)-i)))
+			break
+		}
+		lines = append(lines, "  "+p)
+	}
+	return strings.Join(lines, "\n")
 }
 
-// RunPicker displays the interactive context picker and returns the selected context name.
-func RunPicker(items []PickerItem) (string, error) {
-	if len(items) == 0 {
-		return "", nil
+// RunPicker displays the interactive context picker with one tab per scope,
+// starting on the tab at index active, and returns the selected context.
+// A nil selection means the user cancelled.
+func RunPicker(tabs []PickerTab, active int, actions PickerActions) (*PickerSelection, error) {
+	if len(tabs) == 0 {
+		return nil, nil
 	}
 
-	m := pickerModel{items: items}
-	p := tea.NewProgram(m)
+	m := newPickerModel(tabs, active, actions)
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	result, err := p.Run()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	final := result.(pickerModel)
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("wk", "work"); !ok {
+		t.Error("expected subsequence to match")
+	}
+	if _, ok := fuzzyScore("kw", "work"); ok {
+		t.Error("expected out-of-order runes not to match")
+	}
+	prefix, _ := fuzzyScore("per", "personal")
+	scattered, _ := fuzzyScore("per", "api-server")
+	if prefix <= scattered {
+		t.Errorf("expected prefix match to score higher (%d <= %d)", prefix, scattered)
+	}
+	word, _ := fuzzyScore("cl", "team-client")
+	inner, _ := fuzzyScore("cl", "uncle")
+	if word <= inner {
+		t.Errorf("expected word-start match to score higher (%d <= %d)", word, inner)
+	}
+}
+
+func testTabs() []PickerTab {
+	return []PickerTab{
+		{Scope: "project", Items: []PickerItem{
+			{Name: "backend", Description: "API work"},
+			{Name: "frontend", IsCurrent: true},
+			{Name: "docs", Description: "writing"},
+		}},
+		{Scope: "user", Items: []PickerItem{
+			{Name: "personal", OAuthEmail: "me@example.com", Paths: []string{".claude.json", "dotclaude/settings.json"}},
+		}},
+	}
+}
+
+func send(m pickerModel, msgs ...tea.Msg) pickerModel {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(pickerModel)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func key(t tea.KeyType) tea.KeyMsg {
+	return tea.KeyMsg{Type: t}
+}
+
+func TestPickerFilter(t *testing.T) {
+	m := newPickerModel(testTabs(), 0, PickerActions{})
+
+	m = send(m, runes("fr"))
+	if item, _ := m.current(); len(m.filtered) != 1 || item.Name != "frontend" {
+		t.Fatalf("expected only frontend to match %q, got %v", m.query, m.filtered)
+	}
+
+	m = send(m, key(tea.KeyBackspace), key(tea.KeyBackspace), runes("end"))
+	if len(m.filtered) != 2 {
+		t.Fatalf("expected 2 matches for %q, got %d", m.query, len(m.filtered))
+	}
+
+	m = send(m, key(tea.KeyBackspace), key(tea.KeyBackspace), key(tea.KeyBackspace), runes("writ"))
+	if item, _ := m.current(); len(m.filtered) != 1 || item.Name != "docs" {
+		t.Errorf("expected description match on docs, got %v", m.filtered)
+	}
+
+	m = send(m, key(tea.KeyEsc))
+	if m.query != "" || len(m.filtered) != 3 || m.quitting {
+		t.Error("expected esc to clear the filter without quitting")
+	}
+}
+
+func TestPickerTabsAndSelect(t *testing.T) {
+	m := newPickerModel(testTabs(), 0, PickerActions{})
+	m = send(m, key(tea.KeyTab))
+	if m.tabs[m.tab].Scope != "user" {
+		t.Fatalf("expected user tab, got %q", m.tabs[m.tab].Scope)
+	}
+	if view := m.View(); !strings.Contains(view, "me@example.com") || !strings.Contains(view, "dotclaude/settings.json") {
+		t.Errorf("expected preview with OAuth email and files, got:\n%s", view)
+	}
+
+	next, cmd := m.Update(key(tea.KeyEnter))
+	m = next.(pickerModel)
+	if cmd == nil || m.selected == nil || *m.selected != (PickerSelection{Scope: "user", Name: "personal"}) {
+		t.Errorf("unexpected selection: %+v", m.selected)
+	}
+}
+
+func TestPickerDeleteConfirmation(t *testing.T) {
+	var deleted string
+	actions := PickerActions{Delete: func(scope, name string) ([]PickerItem, error) {
+		deleted = scope + "/" + name
+		return []PickerItem{{Name: "frontend", IsCurrent: true}, {Name: "docs"}}, nil
+	}}
+	m := newPickerModel(testTabs(), 0, actions)
+
+	m = send(m, key(tea.KeyCtrlD), runes("n"))
+	if deleted != "" || m.mode != modeBrowse {
+		t.Fatal("expected declined confirmation not to delete")
+	}
+
+	m = send(m, key(tea.KeyCtrlD), runes("y"))
+	if deleted != "project/backend" {
+		t.Fatalf("expected backend to be deleted, got %q", deleted)
+	}
+	if len(m.items()) != 2 || !strings.Contains(m.status, "Deleted") {
+		t.Errorf("expected refreshed items and status, got %d items, %q", len(m.items()), m.status)
+	}
+
+	m = send(m, key(tea.KeyCtrlD))
+	if m.mode != modeBrowse {
+		t.Error("expected active context to be protected")
+	}
+}
+
+func TestPickerRenameAndDuplicate(t *testing.T) {
+	var calls []string
+	actions := PickerActions{
+		Rename: func(scope, oldName, newName string) ([]PickerItem, error) {
+			calls = append(calls, "rename "+oldName+" "+newName)
+			return nil, errors.New("boom")
+		},
+		Duplicate: func(scope, name, newName string) ([]PickerItem, error) {
+			calls = append(calls, "dup "+name+" "+newName)
+			return []PickerItem{{Name: name}, {Name: newName}}, nil
+		},
+	}
+	m := newPickerModel(testTabs(), 0, actions)
+
+	m = send(m, key(tea.KeyCtrlR), key(tea.KeyBackspace), runes("x"), key(tea.KeyEnter))
+	m = send(m, key(tea.KeyCtrlY), key(tea.KeyEnter))
+	m = send(m, key(tea.KeyCtrlR), key(tea.KeyEsc))
+
+	want := []string{"rename backend backenx", "dup backend backend-copy"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if len(m.items()) != 2 || m.items()[1].Name != "backend-copy" {
+		t.Errorf("expected items refreshed after duplicate, got %+v", m.items())
+	}
+}
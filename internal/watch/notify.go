@@ -0,0 +1,116 @@
+package watch
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// root is a directory to watch. Recursive roots include all subdirectories,
+// including ones created later.
+type root struct {
+	Path      string
+	Recursive bool
+}
+
+// notifier reports paths that may have changed.
+type notifier interface {
+	Events() <-chan string
+	Close() error
+}
+
+// pollNotifier detects changes by comparing periodic snapshots of the
+// watched roots. It is used where inotify is not available.
+type pollNotifier struct {
+	roots  []root
+	events chan string
+	done   chan struct{}
+}
+
+// fileStamp is what pollNotifier compares between snapshots.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+}
+
+func newPollNotifier(roots []root, interval time.Duration) *pollNotifier {
+	n := &pollNotifier{
+		roots:  roots,
+		events: make(chan string, 64),
+		done:   make(chan struct{}),
+	}
+	go n.run(interval)
+	return n
+}
+
+func (n *pollNotifier) Events() <-chan string { return n.events }
+
+func (n *pollNotifier) Close() error {
+	close(n.done)
+	return nil
+}
+
+func (n *pollNotifier) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	prev := n.snapshot()
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+		}
+		next := n.snapshot()
+		for path, stamp := range next {
+			if old, ok := prev[path]; !ok || old != stamp {
+				n.emit(path)
+			}
+		}
+		for path := range prev {
+			if _, ok := next[path]; !ok {
+				n.emit(path)
+			}
+		}
+		prev = next
+	}
+}
+
+func (n *pollNotifier) emit(path string) {
+	select {
+	case n.events <- path:
+	case <-n.done:
+	}
+}
+
+// snapshot stats every file in the watched roots.
+func (n *pollNotifier) snapshot() map[string]fileStamp {
+	files := map[string]fileStamp{}
+	add := func(path string, info fs.FileInfo) {
+		if !info.IsDir() {
+			files[path] = fileStamp{info.Size(), info.ModTime(), info.Mode()}
+		}
+	}
+	for _, r := range n.roots {
+		if r.Recursive {
+			filepath.Walk(r.Path, func(path string, info fs.FileInfo, err error) error {
+				if err == nil {
+					add(path, info)
+				}
+				return nil
+			})
+			continue
+		}
+		entries, err := os.ReadDir(r.Path)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if info, err := e.Info(); err == nil {
+				add(filepath.Join(r.Path, e.Name()), info)
+			}
+		}
+	}
+	return files
+}
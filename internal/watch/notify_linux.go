@@ -0,0 +1,153 @@
+//go:build linux
+
+package watch
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CREATE | unix.IN_CLOSE_WRITE | unix.IN_MODIFY | unix.IN_ATTRIB |
+	unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// inotifyNotifier watches directories with Linux inotify.
+type inotifyNotifier struct {
+	fd     int
+	file   *os.File // non-blocking fd wrapped for the runtime poller; Close unblocks Read
+	roots  []root
+	events chan string
+	done   chan struct{}
+
+	mu   sync.Mutex
+	dirs map[int]string // watch descriptor -> directory
+}
+
+func newNativeNotifier(roots []root) (notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	n := &inotifyNotifier{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		roots:  roots,
+		events: make(chan string, 64),
+		done:   make(chan struct{}),
+		dirs:   map[int]string{},
+	}
+	for _, r := range roots {
+		if err := n.add(r.Path, r.Recursive); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			n.file.Close()
+			return nil, err
+		}
+	}
+	go n.run()
+	return n, nil
+}
+
+func (n *inotifyNotifier) Events() <-chan string { return n.events }
+
+func (n *inotifyNotifier) Close() error {
+	close(n.done)
+	return n.file.Close()
+}
+
+func (n *inotifyNotifier) emit(path string) {
+	select {
+	case n.events <- path:
+	case <-n.done:
+	}
+}
+
+// add watches dir, and all directories below it if recursive.
+func (n *inotifyNotifier) add(dir string, recursive bool) error {
+	if !recursive {
+		return n.addOne(dir)
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return n.addOne(path)
+		}
+		return nil
+	})
+}
+
+func (n *inotifyNotifier) addOne(dir string) error {
+	wd, err := unix.InotifyAddWatch(n.fd, dir, inotifyMask)
+	if err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return fs.ErrNotExist
+		}
+		return &fs.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+	}
+	n.mu.Lock()
+	n.dirs[wd] = dir
+	n.mu.Unlock()
+	return nil
+}
+
+// inRecursiveRoot reports whether path is, or is below, a recursive root.
+func (n *inotifyNotifier) inRecursiveRoot(path string) bool {
+	for _, r := range n.roots {
+		if r.Recursive && (path == r.Path || strings.HasPrefix(path, r.Path+string(filepath.Separator))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *inotifyNotifier) run() {
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		size, err := n.file.Read(buf)
+		if err != nil {
+			return // closed
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= size; {
+			ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(ev.Len)]
+			offset += unix.SizeofInotifyEvent + int(ev.Len)
+
+			n.mu.Lock()
+			dir, ok := n.dirs[int(ev.Wd)]
+			if ev.Mask&unix.IN_IGNORED != 0 {
+				delete(n.dirs, int(ev.Wd))
+			}
+			n.mu.Unlock()
+			if !ok {
+				continue
+			}
+			path := dir
+			if name := string(bytes.TrimRight(nameBytes, "\x00")); name != "" {
+				path = filepath.Join(dir, name)
+			}
+
+			created := ev.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0
+			if created && ev.Mask&unix.IN_ISDIR != 0 && n.inRecursiveRoot(path) {
+				n.add(path, true)
+				// Files written before the watch was added would be missed.
+				filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+					if err == nil && !d.IsDir() {
+						n.emit(p)
+					}
+					return nil
+				})
+			}
+			n.emit(path)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+// newNativeNotifier is only implemented on Linux; other platforms poll.
+func newNativeNotifier(roots []root) (notifier, error) {
+	return nil, errors.New("native file notifications are not supported on this platform")
+}
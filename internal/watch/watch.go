@@ -0,0 +1,197 @@
+// Package watch implements `claudectx watch`: it monitors a scope's live
+// files and saves edits into the active context, keeping the previous
+// snapshot as a revision.
+package watch
+
+import (
+	gocontext "context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+	"github.com/pfldy2850/claudectx/internal/fileutil"
+)
+
+// Default timings.
+const (
+	DefaultDebounce = time.Second
+	DefaultInterval = 2 * time.Second
+)
+
+// Options configures Run.
+type Options struct {
+	Config   *config.Config
+	Debounce time.Duration // quiet period after the last change before saving
+	Interval time.Duration // polling interval when inotify is unavailable
+	Poll     bool          // always poll, even where inotify is available
+
+	// Report is called after every batch of changes. It may be nil.
+	Report func(Event)
+}
+
+// Event describes what Run did with a batch of changes.
+type Event struct {
+	Context string               // active context when the batch was handled
+	Drift   *context.DriftResult // changes that were saved
+	Saved   bool
+	Skipped string // why nothing was saved, if not Saved
+	Err     error  // save failure
+}
+
+// Run watches the live files of opts.Config's scope until ctx is done.
+func Run(ctx gocontext.Context, opts Options) error {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	n, err := newNotifier(opts)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+
+	w := &watcher{Options: opts}
+	var timer *time.Timer
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case path := <-n.Events():
+			if !w.relevant(path) {
+				continue
+			}
+			if fire == nil {
+				// The batch belongs to the context active when it started; a
+				// switch in between means the writes came from a restore.
+				w.batchContext, _ = context.GetCurrent(opts.Config)
+				timer = time.NewTimer(opts.Debounce)
+				fire = timer.C
+			} else {
+				timer.Reset(opts.Debounce)
+			}
+		case <-fire:
+			if context.Restoring(opts.Config) {
+				timer.Reset(opts.Debounce)
+				continue
+			}
+			fire = nil
+			w.report(w.flush())
+		}
+	}
+}
+
+// newNotifier prefers native notifications and falls back to polling.
+func newNotifier(opts Options) (notifier, error) {
+	scope := opts.Config.Scope
+	roots := []root{
+		{Path: scope.DotClaudeDir, Recursive: true},
+		// The parent catches .claude/ being created or replaced.
+		{Path: filepath.Dir(scope.DotClaudeDir)},
+	}
+	for _, ef := range scope.ExtraFiles {
+		roots = append(roots, root{Path: filepath.Dir(ef.Path)})
+	}
+	roots = dedupeRoots(roots)
+
+	if !opts.Poll {
+		if n, err := newNativeNotifier(roots); err == nil {
+			return n, nil
+		}
+	}
+	return newPollNotifier(roots, opts.Interval), nil
+}
+
+func dedupeRoots(roots []root) []root {
+	seen := map[root]bool{}
+	var out []root
+	for _, r := range roots {
+		if !seen[r] {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+type watcher struct {
+	Options
+	batchContext string
+}
+
+// relevant reports whether path is a managed file of the scope (or the
+// .claude directory itself), applying the include/exclude patterns.
+func (w *watcher) relevant(path string) bool {
+	scope := w.Config.Scope
+	for _, ef := range scope.ExtraFiles {
+		if path == ef.Path {
+			return true
+		}
+	}
+	if path == scope.DotClaudeDir {
+		return true
+	}
+	rel, err := filepath.Rel(scope.DotClaudeDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return fileutil.MatchesAny(rel, w.Config.IncludePatterns) && !fileutil.MatchesAny(rel, w.Config.ExcludePatterns)
+}
+
+// flush saves the live files into the active context if they changed.
+func (w *watcher) flush() Event {
+	cfg := w.Config
+	current, _ := context.GetCurrent(cfg)
+	ev := Event{Context: current}
+	switch {
+	case current == "":
+		ev.Skipped = "no active context"
+		return ev
+	case current != w.batchContext:
+		ev.Skipped = fmt.Sprintf("switched from %q; changes came from the switch", w.batchContext)
+		return ev
+	case context.IsLocked(cfg, current):
+		ev.Skipped = "context is locked"
+		return ev
+	}
+
+	drift, err := context.Drift(cfg, current)
+	if err != nil {
+		ev.Err = err
+		return ev
+	}
+	if drift.Clean() {
+		ev.Skipped = "live files match the saved context"
+		return ev
+	}
+	ev.Drift = drift
+
+	description := ""
+	if m, err := context.ReadManifest(filepath.Join(cfg.ContextsDir(), current)); err == nil {
+		description = m.Description
+	}
+	_, err = context.Save(context.SaveOptions{
+		Name:         current,
+		Description:  description,
+		Overwrite:    true,
+		KeepRevision: true,
+		Config:       cfg,
+	})
+	if err != nil {
+		ev.Err = err
+		return ev
+	}
+	ev.Saved = true
+	return ev
+}
+
+func (w *watcher) report(ev Event) {
+	if w.Report != nil {
+		w.Report(ev)
+	}
+}
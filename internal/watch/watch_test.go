@@ -0,0 +1,126 @@
+package watch
+
+import (
+	gocontext "context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+)
+
+func newWatchedScope(t *testing.T) (*config.Config, string) {
+	t.Helper()
+	root := t.TempDir()
+	cfg, err := config.LoadWithScope("", config.ProjectScopeAt(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(root, ".claude"), 0755)
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Work"), 0644)
+	if _, err := context.Save(context.SaveOptions{Name: "work", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	return cfg, root
+}
+
+// startWatch runs the watcher until the test ends and returns its events.
+func startWatch(t *testing.T, cfg *config.Config, poll bool) <-chan Event {
+	t.Helper()
+	events := make(chan Event, 16)
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(ctx, Options{
+			Config:   cfg,
+			Debounce: 100 * time.Millisecond,
+			Interval: 20 * time.Millisecond,
+			Poll:     poll,
+			Report:   func(ev Event) { events <- ev },
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	// Give the notifier time to take its first snapshot or add its watches.
+	time.Sleep(100 * time.Millisecond)
+	return events
+}
+
+func waitEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watcher")
+		return Event{}
+	}
+}
+
+func testWatchSavesEdits(t *testing.T, poll bool) {
+	cfg, root := newWatchedScope(t)
+	events := startWatch(t, cfg, poll)
+
+	os.WriteFile(filepath.Join(root, ".claude", "settings.json"), []byte(`{"model": "opus"}`), 0644)
+	ev := waitEvent(t, events)
+	if !ev.Saved || ev.Context != "work" || len(ev.Drift.Added) != 1 {
+		t.Fatalf("expected the edit to be saved into work, got %+v", ev)
+	}
+	revs, err := context.ListRevisions(cfg, "work")
+	if err != nil || len(revs) != 1 {
+		t.Errorf("expected one revision of the previous snapshot, got %d (%v)", len(revs), err)
+	}
+	saved, _ := os.ReadFile(filepath.Join(cfg.ContextsDir(), "work", "dotclaude", "settings.json"))
+	if string(saved) != `{"model": "opus"}` {
+		t.Errorf("snapshot settings.json = %q", saved)
+	}
+}
+
+func TestWatchSavesEdits(t *testing.T) {
+	t.Run("native", func(t *testing.T) { testWatchSavesEdits(t, false) })
+	t.Run("poll", func(t *testing.T) { testWatchSavesEdits(t, true) })
+}
+
+func TestWatchIgnoresRestore(t *testing.T) {
+	cfg, root := newWatchedScope(t)
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Personal"), 0644)
+	if _, err := context.Save(context.SaveOptions{Name: "personal", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	events := startWatch(t, cfg, false)
+
+	if _, err := context.Restore(context.RestoreOptions{Name: "work", Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	ev := waitEvent(t, events)
+	if ev.Saved {
+		t.Errorf("expected the restore's writes to be ignored, got %+v", ev)
+	}
+	if revs, _ := context.ListRevisions(cfg, "work"); len(revs) != 0 {
+		t.Errorf("expected no revisions of work, got %d", len(revs))
+	}
+}
+
+func TestRelevant(t *testing.T) {
+	cfg, root := newWatchedScope(t)
+	cfg.ExcludePatterns = append(cfg.ExcludePatterns, "todos/**")
+	w := &watcher{Options: Options{Config: cfg}}
+
+	tests := map[string]bool{
+		filepath.Join(root, "CLAUDE.md"):                  true,
+		filepath.Join(root, ".claude", "settings.json"):   true,
+		filepath.Join(root, ".claude", "todos", "a.json"): false,
+		filepath.Join(root, "README.md"):                  false,
+		filepath.Join(root, ".claudectx", "current"):      false,
+	}
+	for path, want := range tests {
+		if got := w.relevant(path); got != want {
+			t.Errorf("relevant(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
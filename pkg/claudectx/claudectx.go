@@ -0,0 +1,77 @@
+// Package claudectx manages Claude Code contexts from Go programs. It is the
+// library behind the claudectx command: a Client is bound to one scope (user
+// or project) and lists, inspects, saves, switches and deletes the contexts
+// stored there.
+//
+// Methods check their context.Context before each step; a file operation that
+// has already started runs to completion.
+package claudectx
+
+import (
+	"github.com/pfldy2850/claudectx/internal/client"
+	"github.com/pfldy2850/claudectx/internal/config"
+	"github.com/pfldy2850/claudectx/internal/context"
+)
+
+// Types shared with the internal implementation.
+type (
+	Client        = client.Client
+	Info          = client.Info
+	SaveOptions   = client.SaveOptions
+	SwitchOptions = client.SwitchOptions
+	SwitchResult  = client.SwitchResult
+	DeleteOptions = client.DeleteOptions
+	DeleteResult  = client.DeleteResult
+	Manifest      = context.Manifest
+	FileEntry     = context.FileEntry
+	SaveResult    = context.SaveResult
+	RestoreResult = context.RestoreResult
+	DriftResult   = context.DriftResult
+)
+
+// Errors wrapped by Client methods. Test for them with errors.Is.
+var (
+	ErrNotFound      = context.ErrNotFound
+	ErrExists        = context.ErrExists
+	ErrInvalidName   = context.ErrInvalidName
+	ErrScopeMismatch = context.ErrScopeMismatch
+	ErrLocked        = context.ErrLocked
+	ErrActive        = context.ErrActive
+	ErrInvalidFiles  = context.ErrInvalidFiles
+	ErrUncommitted   = context.ErrUncommitted
+	ErrInvalidScope  = config.ErrInvalidScope
+	ErrInvalidConfig = config.ErrInvalidConfig
+)
+
+// Options configures a Client.
+type Options struct {
+	Scope      string // "user", "project", "local", "managed" or a custom scope; empty auto-detects like the CLI
+	Root       string // explicit project root; implies project scope
+	ConfigPath string // config file; empty uses <storage dir>/config.json
+	StorageDir string // overrides where contexts are stored
+
+	// Confirm answers yes/no questions, such as whether to overwrite a
+	// snapshot whose live files look broken or to delete a context. If nil,
+	// the non-interactive default is used.
+	Confirm func(question string) bool
+}
+
+// New resolves the scope and loads the claudectx config described by opts.
+func New(opts Options) (*Client, error) {
+	scope, err := config.ResolveScopeWithRoot(opts.Scope, opts.Root)
+	if err != nil {
+		return nil, err
+	}
+	if opts.StorageDir != "" {
+		scope.StorageDir = opts.StorageDir
+	}
+	cfg, err := config.LoadWithScope(opts.ConfigPath, scope)
+	if err != nil {
+		return nil, err
+	}
+	if opts.StorageDir != "" {
+		cfg.StorageDir = opts.StorageDir
+	}
+	cfg.Confirm = opts.Confirm
+	return client.New(cfg), nil
+}
@@ -0,0 +1,107 @@
+package claudectx
+
+import (
+	gocontext "context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestClient(t *testing.T) (*Client, string) {
+	t.Helper()
+	root := t.TempDir()
+	c, err := New(Options{Root: root, StorageDir: filepath.Join(t.TempDir(), "store")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, root
+}
+
+func TestClientLifecycle(t *testing.T) {
+	c, root := newTestClient(t)
+	ctx := gocontext.Background()
+	claudeMD := filepath.Join(root, "CLAUDE.md")
+
+	os.WriteFile(claudeMD, []byte("# Work"), 0644)
+	if _, err := c.Save(ctx, SaveOptions{Name: "work", Description: "Work"}); err != nil {
+		t.Fatal(err)
+	}
+	// Forget the active context so saving the next one doesn't auto-save
+	// the live edits into "work".
+	os.Remove(filepath.Join(c.StorageDir(), "current"))
+	os.WriteFile(claudeMD, []byte("# Personal"), 0644)
+	if _, err := c.Save(ctx, SaveOptions{Name: "personal"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(c.StorageDir(), "contexts", "work")); err != nil {
+		t.Fatalf("expected contexts under the StorageDir option: %v", err)
+	}
+
+	list, err := c.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "personal" || !list[0].Current || list[1].Current {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	res, err := c.Switch(ctx, "work", SwitchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Previous != "personal" || res.FilesRestored != 1 {
+		t.Errorf("unexpected switch result: %+v", res)
+	}
+	if data, _ := os.ReadFile(claudeMD); string(data) != "# Work" {
+		t.Errorf("CLAUDE.md = %q after switch", data)
+	}
+	if res, _ := c.Switch(ctx, "work", SwitchOptions{}); !res.AlreadyActive {
+		t.Error("expected switching to the active context to be a no-op")
+	}
+
+	info, err := c.Show(ctx, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Description != "Work" || !info.Current {
+		t.Errorf("unexpected show result: %+v", info)
+	}
+
+	os.WriteFile(claudeMD, []byte("# Edited"), 0644)
+	drift, err := c.Diff(ctx, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift.Modified) != 1 || drift.Modified[0] != "CLAUDE.md" {
+		t.Errorf("unexpected diff: %+v", drift)
+	}
+
+	if _, err := c.Delete(ctx, "work", DeleteOptions{}); !errors.Is(err, ErrActive) {
+		t.Errorf("expected deleting the active context to fail with ErrActive, got %v", err)
+	}
+	del, err := c.Delete(ctx, "personal", DeleteOptions{})
+	if err != nil || !del.Deleted {
+		t.Fatalf("delete failed: %+v, %v", del, err)
+	}
+	if _, err := c.Show(ctx, "personal"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestClientCancelled(t *testing.T) {
+	c, root := newTestClient(t)
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Work"), 0644)
+
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+	if _, err := c.Save(ctx, SaveOptions{Name: "work"}); !errors.Is(err, gocontext.Canceled) {
+		t.Errorf("expected Save to honor cancellation, got %v", err)
+	}
+	if _, err := c.List(gocontext.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := c.List(gocontext.Background()); len(list) != 0 {
+		t.Errorf("expected nothing saved after cancellation, got %+v", list)
+	}
+}